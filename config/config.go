@@ -0,0 +1,477 @@
+// config パッケージはアプリケーションの設定を提供します。
+//
+// 設定は以下の順に読み込まれ、後から読み込まれたものほど優先されます。
+//  1. デフォルト値
+//  2. 設定ファイル（YAML）。--config フラグあるいは環境変数 APP_CONFIG_FILE でパスを指定した場合のみ読み込まれます。
+//  3. 環境変数（APP_ で始まるもの）
+//  4. コマンドライン引数（フラグ）
+//
+// 各設定項目のフラグ名・環境変数名・設定ファイルのキーは [Load] の説明を参照してください。
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// 環境変数名の接頭辞。
+const envPrefix = "APP_"
+
+// アプリケーションの設定。
+type Config struct {
+	// 動作モード。production（本番）か development（開発）です。
+	// development の場合、エラーレスポンスに内部のエラーの内容が含まれます。
+	Mode string `yaml:"mode"`
+	// ユーザーの保存先。mongo（MongoDB）か memory（メモリ上。プロセス終了時に失われます）です。
+	Store string `yaml:"store"`
+	// MongoDB の設定。Store が mongo の場合のみ使用されます。
+	Mongo MongoConfig `yaml:"mongo"`
+	// HTTP サーバーの設定。
+	Server ServerConfig `yaml:"server"`
+	// ログの設定。
+	Log LogConfig `yaml:"log"`
+	// 削除済みユーザーの完全削除の設定。
+	Purge PurgeConfig `yaml:"purge"`
+	// 凍結の期限が過ぎたユーザーの凍結解除の設定。
+	Unfreeze UnfreezeConfig `yaml:"unfreeze"`
+	// レート制限の設定。
+	RateLimit RateLimitConfig `yaml:"rateLimit"`
+}
+
+// MongoDB の設定。
+type MongoConfig struct {
+	// MongoDB の URI。認証情報を含むことがあるため、ログ等に出力する場合は [MongoConfig.RedactedURI] を使用してください。
+	URI string `yaml:"uri"`
+	// データベース名。
+	Database string `yaml:"database"`
+	// 接続のタイムアウト。
+	ConnectTimeout time.Duration `yaml:"connectTimeout"`
+}
+
+// HTTP サーバーの設定。
+type ServerConfig struct {
+	// 待ち受けるアドレス（例: ":8080"）。
+	Address string `yaml:"address"`
+	// リクエストの読み込みのタイムアウト。
+	ReadTimeout time.Duration `yaml:"readTimeout"`
+	// レスポンスの書き込みのタイムアウト。
+	WriteTimeout time.Duration `yaml:"writeTimeout"`
+	// シャットダウン時に、処理中のリクエストの完了を待つ時間の上限。
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	// シャットダウン時に、Readiness を準備未完了にしてから新しいリクエストの受け付けを止めるまで待つ時間。
+	// オーケストレーターが Readiness の変化を検知して、振り分け先から外すまでの時間を見込んで設定します。
+	ShutdownDelay time.Duration `yaml:"shutdownDelay"`
+}
+
+// ログの設定。
+type LogConfig struct {
+	// ログレベル。debug, info, warn, error のいずれかです。
+	Level string `yaml:"level"`
+}
+
+// 削除済みユーザーの完全削除の設定。
+type PurgeConfig struct {
+	// 削除済みのユーザーを保持する期間。削除してからこの期間が経過したユーザーは完全に削除され、復元できなくなります。
+	Retention time.Duration `yaml:"retention"`
+	// 完全に削除するユーザーを探す間隔。
+	Interval time.Duration `yaml:"interval"`
+}
+
+// 凍結の期限が過ぎたユーザーの凍結解除の設定。
+type UnfreezeConfig struct {
+	// 凍結の期限が過ぎたユーザーを探す間隔。期限が過ぎてから最大でこの時間だけ、ステータスが frozen のまま残ります。
+	Interval time.Duration `yaml:"interval"`
+}
+
+// レート制限の設定。レート制限はクライアントの IP アドレス毎に、レート制限の種類毎に行います。
+type RateLimitConfig struct {
+	// ユーザーを変更しないユースケース（取得や一覧）のレート制限。
+	Read RateLimitClassConfig `yaml:"read"`
+	// ユーザーを変更するユースケース（作成や更新、削除）のレート制限。
+	Write RateLimitClassConfig `yaml:"write"`
+}
+
+// レート制限の種類毎の設定。
+type RateLimitClassConfig struct {
+	// 1 秒あたりに許可するリクエスト数。
+	Rate float64 `yaml:"rate"`
+	// 一時的に Rate を超えて許可するリクエスト数。
+	Burst int `yaml:"burst"`
+}
+
+// デフォルトの設定を返します。
+func Default() *Config {
+	return &Config{
+		Mode:  "production",
+		Store: "mongo",
+		Mongo: MongoConfig{
+			URI:            "mongodb://localhost:27017",
+			Database:       "user_management",
+			ConnectTimeout: 10 * time.Second,
+		},
+		Server: ServerConfig{
+			Address:         ":8080",
+			ReadTimeout:     30 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			ShutdownDelay:   5 * time.Second,
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Purge: PurgeConfig{
+			Retention: 30 * 24 * time.Hour,
+			Interval:  1 * time.Hour,
+		},
+		Unfreeze: UnfreezeConfig{
+			Interval: 1 * time.Minute,
+		},
+		RateLimit: RateLimitConfig{
+			Read:  RateLimitClassConfig{Rate: 20, Burst: 40},
+			Write: RateLimitClassConfig{Rate: 5, Burst: 10},
+		},
+	}
+}
+
+// 設定項目。フラグと環境変数から値を設定するために使用します。
+type setting struct {
+	// フラグ名。環境変数名は、これを大文字にして - を _ に置き換え、APP_ を付けたものです。
+	name string
+	// フラグの説明。
+	usage string
+	// 設定に値を反映する関数。
+	set func(config *Config, value string) error
+}
+
+// フラグと環境変数で指定できる設定項目の一覧。
+var settings = []setting{
+	{"mode", "動作モード（production か development）", setString(func(c *Config) *string { return &c.Mode })},
+	{"store", "ユーザーの保存先（mongo か memory）", setString(func(c *Config) *string { return &c.Store })},
+	{"mongo-uri", "MongoDB の URI", setString(func(c *Config) *string { return &c.Mongo.URI })},
+	{"mongo-database", "MongoDB のデータベース名", setString(func(c *Config) *string { return &c.Mongo.Database })},
+	{"mongo-connect-timeout", "MongoDB への接続のタイムアウト（例: 10s）", setDuration(func(c *Config) *time.Duration { return &c.Mongo.ConnectTimeout })},
+	{"listen-address", "HTTP サーバーが待ち受けるアドレス（例: :8080）", setString(func(c *Config) *string { return &c.Server.Address })},
+	{"read-timeout", "リクエストの読み込みのタイムアウト（例: 30s）", setDuration(func(c *Config) *time.Duration { return &c.Server.ReadTimeout })},
+	{"write-timeout", "レスポンスの書き込みのタイムアウト（例: 30s）", setDuration(func(c *Config) *time.Duration { return &c.Server.WriteTimeout })},
+	{"shutdown-timeout", "シャットダウン時に処理中のリクエストの完了を待つ時間の上限（例: 30s）", setDuration(func(c *Config) *time.Duration { return &c.Server.ShutdownTimeout })},
+	{"shutdown-delay", "シャットダウン時に Readiness を準備未完了にしてから新しいリクエストの受け付けを止めるまで待つ時間（例: 5s）", setDuration(func(c *Config) *time.Duration { return &c.Server.ShutdownDelay })},
+	{"log-level", "ログレベル（debug, info, warn, error のいずれか）", setString(func(c *Config) *string { return &c.Log.Level })},
+	{"purge-retention", "削除済みのユーザーを完全に削除するまで保持する期間（例: 720h）", setDuration(func(c *Config) *time.Duration { return &c.Purge.Retention })},
+	{"purge-interval", "完全に削除する削除済みのユーザーを探す間隔（例: 1h）", setDuration(func(c *Config) *time.Duration { return &c.Purge.Interval })},
+	{"unfreeze-interval", "凍結の期限が過ぎたユーザーを探す間隔（例: 1m）", setDuration(func(c *Config) *time.Duration { return &c.Unfreeze.Interval })},
+	{"rate-limit-read-rate", "取得や一覧のユースケースで、クライアント毎に 1 秒あたりに許可するリクエスト数（例: 20）", setFloat(func(c *Config) *float64 { return &c.RateLimit.Read.Rate })},
+	{"rate-limit-read-burst", "取得や一覧のユースケースで、クライアント毎に一時的に超えて許可するリクエスト数（例: 40）", setInt(func(c *Config) *int { return &c.RateLimit.Read.Burst })},
+	{"rate-limit-write-rate", "ユーザーを変更するユースケースで、クライアント毎に 1 秒あたりに許可するリクエスト数（例: 5）", setFloat(func(c *Config) *float64 { return &c.RateLimit.Write.Rate })},
+	{"rate-limit-write-burst", "ユーザーを変更するユースケースで、クライアント毎に一時的に超えて許可するリクエスト数（例: 10）", setInt(func(c *Config) *int { return &c.RateLimit.Write.Burst })},
+}
+
+func setString(field func(c *Config) *string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+func setDuration(field func(c *Config) *time.Duration) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = d
+		return nil
+	}
+}
+
+func setFloat(field func(c *Config) *float64) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*field(c) = f
+		return nil
+	}
+}
+
+func setInt(field func(c *Config) *int) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = i
+		return nil
+	}
+}
+
+// 設定項目の環境変数名を返します。
+func (s *setting) envName() string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(s.name, "-", "_"))
+}
+
+// 設定を読み込み、バリデーションして返します。
+// args はコマンドライン引数（プログラム名を除いたもの）、getenv は環境変数を取得する関数（通常は os.Getenv）です。
+//
+// 設定項目と、対応するフラグ名・環境変数名・設定ファイルのキーは以下のとおりです。
+//
+//	フラグ                    環境変数                   設定ファイルのキー
+//	--config                  APP_CONFIG_FILE            （設定ファイルのパス）
+//	--mode                    APP_MODE                   mode
+//	--store                   APP_STORE                  store
+//	--mongo-uri               APP_MONGO_URI              mongo.uri
+//	--mongo-database          APP_MONGO_DATABASE         mongo.database
+//	--mongo-connect-timeout   APP_MONGO_CONNECT_TIMEOUT  mongo.connectTimeout
+//	--listen-address          APP_LISTEN_ADDRESS         server.address
+//	--read-timeout            APP_READ_TIMEOUT           server.readTimeout
+//	--write-timeout           APP_WRITE_TIMEOUT          server.writeTimeout
+//	--shutdown-timeout        APP_SHUTDOWN_TIMEOUT       server.shutdownTimeout
+//	--shutdown-delay          APP_SHUTDOWN_DELAY         server.shutdownDelay
+//	--log-level               APP_LOG_LEVEL              log.level
+//	--purge-retention         APP_PURGE_RETENTION        purge.retention
+//	--purge-interval          APP_PURGE_INTERVAL         purge.interval
+//	--unfreeze-interval       APP_UNFREEZE_INTERVAL      unfreeze.interval
+//	--rate-limit-read-rate    APP_RATE_LIMIT_READ_RATE   rateLimit.read.rate
+//	--rate-limit-read-burst   APP_RATE_LIMIT_READ_BURST  rateLimit.read.burst
+//	--rate-limit-write-rate   APP_RATE_LIMIT_WRITE_RATE  rateLimit.write.rate
+//	--rate-limit-write-burst  APP_RATE_LIMIT_WRITE_BURST rateLimit.write.burst
+//
+// -h あるいは --help が指定された場合は、使い方を出力して flag.ErrHelp を返します。
+func Load(args []string, getenv func(string) string) (*Config, error) {
+	return load(args, getenv, os.Stderr)
+}
+
+func load(args []string, getenv func(string) string, output io.Writer) (*Config, error) {
+	// フラグは最も優先されるが、設定ファイルのパスを知るために最初にパースしておく
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(output)
+	configFile := fs.String("config", "", "設定ファイル（YAML）のパス。環境変数 APP_CONFIG_FILE でも指定できます")
+	flagValues := make(map[string]*string, len(settings))
+	for _, s := range settings {
+		flagValues[s.name] = fs.String(s.name, "", fmt.Sprintf("%s。環境変数 %s でも指定できます", s.usage, s.envName()))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("不明な引数が指定されました: %v", fs.Args())
+	}
+
+	config := Default()
+
+	if *configFile == "" {
+		*configFile = getenv(envPrefix + "CONFIG_FILE")
+	}
+	if *configFile != "" {
+		if err := config.loadFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value := getenv(s.envName()); value != "" {
+			if err := s.set(config, value); err != nil {
+				return nil, fmt.Errorf("環境変数 %s の値が不正です: %w", s.envName(), err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.name == f.Name && flagErr == nil {
+				if err := s.set(config, *flagValues[s.name]); err != nil {
+					flagErr = fmt.Errorf("フラグ --%s の値が不正です: %w", s.name, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("設定が不正です: %w", err)
+	}
+
+	return config, nil
+}
+
+// 設定ファイルを読み込み、設定に反映します。設定ファイルに記載されていない項目は変更しません。
+func (config *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("設定ファイルを開けませんでした: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("設定ファイル %s の読み込みに失敗しました: %w", path, err)
+	}
+	return nil
+}
+
+func (config *Config) validate() error {
+	return validation.ValidateStruct(config,
+		validation.Field(&config.Mode,
+			validation.Required.Error("動作モードは必須です"),
+			validation.In("production", "development").Error("動作モードは production か development です"),
+		),
+		validation.Field(&config.Store,
+			validation.Required.Error("ユーザーの保存先は必須です"),
+			validation.In("mongo", "memory").Error("ユーザーの保存先は mongo か memory です"),
+		),
+		validation.Field(&config.Mongo,
+			validation.When(config.Store == "mongo", validation.By(func(interface{}) error {
+				return config.Mongo.validate()
+			})),
+		),
+		validation.Field(&config.Server, validation.By(func(interface{}) error {
+			return config.Server.validate()
+		})),
+		validation.Field(&config.Log, validation.By(func(interface{}) error {
+			return config.Log.validate()
+		})),
+		validation.Field(&config.Purge, validation.By(func(interface{}) error {
+			return config.Purge.validate()
+		})),
+		validation.Field(&config.Unfreeze, validation.By(func(interface{}) error {
+			return config.Unfreeze.validate()
+		})),
+		validation.Field(&config.RateLimit, validation.By(func(interface{}) error {
+			return config.RateLimit.validate()
+		})),
+	)
+}
+
+func (config *MongoConfig) validate() error {
+	return validation.ValidateStruct(config,
+		validation.Field(&config.URI,
+			validation.Required.Error("MongoDB の URI は必須です"),
+			validation.By(func(interface{}) error {
+				// Note: エラーメッセージに URI（認証情報を含みうる）を含めないようにしています。
+				u, err := url.Parse(config.URI)
+				if err != nil || (u.Scheme != "mongodb" && u.Scheme != "mongodb+srv") {
+					return errors.New("MongoDB の URI は mongodb:// か mongodb+srv:// で始まる URI です")
+				}
+				return nil
+			}),
+		),
+		validation.Field(&config.Database,
+			validation.Required.Error("MongoDB のデータベース名は必須です"),
+		),
+		validation.Field(&config.ConnectTimeout,
+			validation.Required.Error("MongoDB への接続のタイムアウトは必須です"),
+			validation.Min(time.Duration(0)).Exclusive().Error("MongoDB への接続のタイムアウトは正の値です"),
+		),
+	)
+}
+
+func (config *ServerConfig) validate() error {
+	return validation.ValidateStruct(config,
+		validation.Field(&config.Address,
+			validation.Required.Error("待ち受けるアドレスは必須です"),
+		),
+		validation.Field(&config.ReadTimeout,
+			validation.Required.Error("リクエストの読み込みのタイムアウトは必須です"),
+			validation.Min(time.Duration(0)).Exclusive().Error("リクエストの読み込みのタイムアウトは正の値です"),
+		),
+		validation.Field(&config.WriteTimeout,
+			validation.Required.Error("レスポンスの書き込みのタイムアウトは必須です"),
+			validation.Min(time.Duration(0)).Exclusive().Error("レスポンスの書き込みのタイムアウトは正の値です"),
+		),
+		validation.Field(&config.ShutdownTimeout,
+			validation.Required.Error("シャットダウンのタイムアウトは必須です"),
+			validation.Min(time.Duration(0)).Exclusive().Error("シャットダウンのタイムアウトは正の値です"),
+		),
+		validation.Field(&config.ShutdownDelay,
+			validation.Min(time.Duration(0)).Error("シャットダウンの待ち時間は 0 以上です"),
+		),
+	)
+}
+
+func (config *LogConfig) validate() error {
+	return validation.ValidateStruct(config,
+		validation.Field(&config.Level,
+			validation.Required.Error("ログレベルは必須です"),
+			validation.In("debug", "info", "warn", "error").Error("ログレベルは debug, info, warn, error のいずれかです"),
+		),
+	)
+}
+
+func (config *PurgeConfig) validate() error {
+	return validation.ValidateStruct(config,
+		validation.Field(&config.Retention,
+			validation.Min(time.Duration(0)).Error("削除済みのユーザーの保持期間は 0 以上です"),
+		),
+		validation.Field(&config.Interval,
+			validation.Required.Error("削除済みのユーザーを完全に削除する間隔は必須です"),
+			validation.Min(time.Duration(0)).Exclusive().Error("削除済みのユーザーを完全に削除する間隔は正の値です"),
+		),
+	)
+}
+
+func (config *UnfreezeConfig) validate() error {
+	return validation.ValidateStruct(config,
+		validation.Field(&config.Interval,
+			validation.Required.Error("凍結の期限が過ぎたユーザーを探す間隔は必須です"),
+			validation.Min(time.Duration(0)).Exclusive().Error("凍結の期限が過ぎたユーザーを探す間隔は正の値です"),
+		),
+	)
+}
+
+func (config *RateLimitConfig) validate() error {
+	return validation.ValidateStruct(config,
+		validation.Field(&config.Read, validation.By(func(interface{}) error {
+			return config.Read.validate()
+		})),
+		validation.Field(&config.Write, validation.By(func(interface{}) error {
+			return config.Write.validate()
+		})),
+	)
+}
+
+func (config *RateLimitClassConfig) validate() error {
+	return validation.ValidateStruct(config,
+		validation.Field(&config.Rate,
+			validation.Required.Error("1 秒あたりに許可するリクエスト数は必須です"),
+			validation.Min(0.0).Exclusive().Error("1 秒あたりに許可するリクエスト数は正の値です"),
+		),
+		validation.Field(&config.Burst,
+			validation.Required.Error("一時的に超えて許可するリクエスト数は必須です"),
+			validation.Min(1).Error("一時的に超えて許可するリクエスト数は 1 以上です"),
+		),
+	)
+}
+
+// パスワードを伏せた MongoDB の URI を返します。ログ等に出力する場合はこちらを使用してください。
+func (config *MongoConfig) RedactedURI() string {
+	u, err := url.Parse(config.URI)
+	if err != nil {
+		// パースできない URI はどこに認証情報が含まれているかわからないので、すべて伏せる
+		return "xxxxx"
+	}
+	return u.Redacted()
+}
+
+// 設定を文字列で返します。MongoDB の URI に含まれるパスワードは伏せられます。
+func (config Config) String() string {
+	return fmt.Sprintf("{Mode:%s Store:%s Mongo:%v Server:%+v Log:%+v Purge:%+v Unfreeze:%+v RateLimit:%+v}", config.Mode, config.Store, config.Mongo, config.Server, config.Log, config.Purge, config.Unfreeze, config.RateLimit)
+}
+
+// 設定を文字列で返します。URI に含まれるパスワードは伏せられます。
+func (config MongoConfig) String() string {
+	return fmt.Sprintf("{URI:%s Database:%s ConnectTimeout:%s}", config.RedactedURI(), config.Database, config.ConnectTimeout)
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"sync"
+	"time"
+)
+
+// 現在日時を返す時計。
+// 現在日時によって結果が変わる処理は、テストで現在日時を差し替えられるように、時計を受け取るようにしてください。
+type Clock interface {
+	// 現在日時 (UTC) を返します。
+	Now() time.Time
+}
+
+// システムの時計。time.Now を UTC で返します。
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// テスト用の、現在日時を自由に変更できる時計。複数のゴルーチンから同時に使用できます。
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// 現在日時が now の FakeClock を返します。
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now.UTC()}
+}
+
+func (clock *FakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+// 現在日時を d だけ進めます。
+func (clock *FakeClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+}
@@ -0,0 +1,11 @@
+// repositorytest パッケージは、ドメインのリポジトリの実装が
+// インターフェースに記載された仕様を満たしているかを確認する、共通のテストスイートを提供します。
+//
+// 各リポジトリの実装のテストから、次のように呼び出します。
+//
+//	func TestUserRepository(t *testing.T) {
+//		repositorytest.TestUserRepository(t, func(t *testing.T) domain.UserRepository {
+//			return NewXxxUserRepository(...)
+//		})
+//	}
+package repositorytest
@@ -0,0 +1,748 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// テストでユーザーを削除する日時。
+var testDeletedAt = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// 空の UserRepository を作成する関数。
+// テストケース毎に呼び出されるので、呼び出し毎に（他のテストケースと干渉しない）空のリポジトリを返してください。
+// 後片付けが必要な場合は t.Cleanup を使用してください。
+type NewUserRepositoryFunc func(t *testing.T) domain.UserRepository
+
+// UserRepository の実装が domain.UserRepository の仕様を満たしていることをテストします。
+func TestUserRepository(t *testing.T, newRepository NewUserRepositoryFunc) {
+	t.Run("Get と Put のテスト", func(t *testing.T) {
+		testGetAndPut(t, newRepository(t))
+	})
+	t.Run("同じ ID のユーザーを保存すると上書きされることのテスト", func(t *testing.T) {
+		testPutOverwritesExistingUser(t, newRepository(t))
+	})
+	t.Run("Put がバージョンの一致する場合のみ保存することのテスト", func(t *testing.T) {
+		testPutConflict(t, newRepository(t))
+	})
+	t.Run("List のテスト", func(t *testing.T) {
+		testList(t, newRepository(t))
+	})
+	t.Run("Delete のテスト", func(t *testing.T) {
+		testDelete(t, newRepository(t))
+	})
+	t.Run("Delete が冪等であることのテスト", func(t *testing.T) {
+		testDeleteIdempotency(t, newRepository(t))
+	})
+	t.Run("List が削除済みのユーザーを含まないことのテスト", func(t *testing.T) {
+		testListExcludesDeletedUsers(t, newRepository(t))
+	})
+	t.Run("削除済みのユーザーを復元できることのテスト", func(t *testing.T) {
+		testRestore(t, newRepository(t))
+	})
+	t.Run("Purge のテスト", func(t *testing.T) {
+		testPurge(t, newRepository(t))
+	})
+	t.Run("ListExpiredFrozen のテスト", func(t *testing.T) {
+		testListExpiredFrozen(t, newRepository(t))
+	})
+}
+
+// Get と Put のテスト。
+func testGetAndPut(t *testing.T, repo domain.UserRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	suspendedUntil := time.Date(4000, time.February, 1, 0, 0, 0, 0, time.UTC)
+	frozenUntil := time.Date(5000, time.January, 9, 0, 0, 0, 0, time.UTC)
+
+	testCases := []domain.User{
+		{
+			UserID:       "U1",
+			Name:         "ユーザー1",
+			Status:       domain.UserStatusNormal,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			UserID:       "U2",
+			Name:         "ユーザー2",
+			Status:       domain.UserStatusFrozen,
+			RegisteredAt: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedAt:    time.Date(2000, time.January, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			UserID:       "U3",
+			Name:         "ユーザー3",
+			Status:       domain.UserStatusFrozen,
+			RegisteredAt: time.Date(3000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			LastStatusChange: &domain.UserStatusChange{
+				OperatorID: "OP1",
+				Reason:     "凍結理由",
+				ChangedAt:  time.Date(3000, time.January, 2, 0, 0, 0, 0, time.UTC),
+			},
+			UpdatedAt: time.Date(3000, time.January, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			UserID:       "U4",
+			Name:         "ユーザー4",
+			Status:       domain.UserStatusSuspended,
+			RegisteredAt: time.Date(4000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			LastStatusChange: &domain.UserStatusChange{
+				OperatorID: "OP1",
+				Reason:     "利用停止理由",
+				ChangedAt:  time.Date(4000, time.January, 2, 0, 0, 0, 0, time.UTC),
+			},
+			UpdatedAt:      time.Date(4000, time.January, 2, 0, 0, 0, 0, time.UTC),
+			SuspendedUntil: &suspendedUntil,
+		},
+		{
+			UserID:       "U5",
+			Name:         "ユーザー5",
+			Status:       domain.UserStatusFrozen,
+			RegisteredAt: time.Date(5000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			LastStatusChange: &domain.UserStatusChange{
+				OperatorID: "OP1",
+				Reason:     "期限付きの凍結理由",
+				ChangedAt:  time.Date(5000, time.January, 2, 0, 0, 0, 0, time.UTC),
+			},
+			UpdatedAt:   time.Date(5000, time.January, 2, 0, 0, 0, 0, time.UTC),
+			FrozenUntil: &frozenUntil,
+		},
+	}
+
+	for _, user := range testCases {
+		t.Run(fmt.Sprintf("ユーザーID=%q", user.UserID), func(t *testing.T) {
+			// 保存前にユーザーを取得しようとすると domain.ErrUserNotFound エラーが発生することを確認
+			_, err := repo.Get(ctx, user.UserID)
+			if err == nil {
+				t.Fatalf("保存前のユーザーを取得しようとしましたが、エラーが発生しませんでした")
+			}
+			if !errors.Is(err, domain.ErrUserNotFound) {
+				t.Fatalf("保存前のユーザーを取得しようとしましたが、予期しないエラーが発生しました: %v", err)
+			}
+
+			// ユーザーを保存
+			if err := repo.Put(ctx, &user); err != nil {
+				t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+			}
+
+			// 保存したユーザーが取得されることを確認
+			gotUser, err := repo.Get(ctx, user.UserID)
+			if err != nil {
+				t.Fatalf("保存したユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+			}
+			if diff := cmp.Diff(user, *gotUser); diff != "" {
+				t.Fatalf("保存したユーザー (-) と取得したユーザー (+) が一致しませんでした:\n%s", diff)
+			}
+
+			// 削除されていないユーザーは GetIncludingDeleted でも同じように取得されることを確認
+			gotUser, err = repo.GetIncludingDeleted(ctx, user.UserID)
+			if err != nil {
+				t.Fatalf("保存したユーザーを GetIncludingDeleted で取得しようとしましたが、エラーが発生しました: %v", err)
+			}
+			if diff := cmp.Diff(user, *gotUser); diff != "" {
+				t.Fatalf("保存したユーザー (-) と GetIncludingDeleted で取得したユーザー (+) が一致しませんでした:\n%s", diff)
+			}
+
+			// 取得したユーザーを変更しても、保存されているユーザーは変わらないことを確認
+			gotUser.Name = "変更後"
+			gotAgain, err := repo.Get(ctx, user.UserID)
+			if err != nil {
+				t.Fatalf("保存したユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+			}
+			if gotAgain.Name != user.Name {
+				t.Fatalf("取得したユーザーを保存せずに変更したところ、保存されているユーザーの名前が %q に変わりました", gotAgain.Name)
+			}
+		})
+	}
+}
+
+// 同じ ID のユーザーを保存すると上書きされることのテスト。
+func testPutOverwritesExistingUser(t *testing.T, repo domain.UserRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	user := domain.User{
+		UserID:       "U1",
+		Name:         "ユーザーA",
+		Status:       domain.UserStatusNormal,
+		RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := repo.Put(ctx, &user); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+
+	// 名前を変えて同じユーザーをもう一度保存
+	if _, err := user.ChangeName("ユーザーB", domain.SystemClock); err != nil {
+		t.Fatalf("ユーザーの名前の変更に失敗しました: %v", err)
+	}
+	if err := repo.Put(ctx, &user); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+
+	// ユーザーが１人しか取得されない（上書きされている）ことを確認
+	gotUsers, _, err := repo.List(ctx, "", -1)
+	if err != nil {
+		t.Fatalf("ユーザーの一覧を取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	if len(gotUsers) == 0 {
+		t.Fatalf("ユーザーの一覧を取得しようとしましたが、一人も取得できませんでした")
+	}
+	if len(gotUsers) != 1 {
+		t.Fatalf("ユーザーは１人しか保存されていないはずなのに、ユーザーの一覧取得で以下のユーザーが取得されました: %+v", gotUsers)
+	}
+
+	// 取得されたユーザーの名前が、名前変更後に保存されたものである（上書きされている）ことを確認
+	if gotUsers[0].Name != "ユーザーB" {
+		t.Fatalf("ユーザーの名前を %q にして上書き保存したはずなのに、取得されたユーザーの名前は %q でした", "ユーザーB", gotUsers[0].Name)
+	}
+}
+
+// Put がバージョンの一致する場合のみ保存し、一致しない場合は domain.ErrConflict を返すことのテスト。
+func testPutConflict(t *testing.T, repo domain.UserRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	user := domain.User{
+		UserID:       "U1",
+		Name:         "ユーザーA",
+		Status:       domain.UserStatusNormal,
+		RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// 保存されていないユーザーは、バージョンが 0 でなければ保存できない
+	unsaved := user
+	unsaved.Version = 1
+	if err := repo.Put(ctx, &unsaved); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("保存されていないユーザーをバージョン 1 で保存しようとしましたが、domain.ErrConflict ではなく %v が返りました", err)
+	}
+
+	if err := repo.Put(ctx, &user); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+	if user.Version != 1 {
+		t.Fatalf("新しいユーザーを保存した後のバージョンは 1 のはずですが、%d でした", user.Version)
+	}
+
+	// 別の操作が取得したユーザーを先に保存する
+	other, err := repo.Get(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("保存したユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	other.Name = "ユーザーB"
+	if err := repo.Put(ctx, other); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+	if other.Version != 2 {
+		t.Fatalf("バージョン 1 のユーザーを保存した後のバージョンは 2 のはずですが、%d でした", other.Version)
+	}
+
+	// 古いバージョンのユーザーは保存できない
+	user.Name = "ユーザーC"
+	if err := repo.Put(ctx, &user); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("古いバージョンのユーザーを保存しようとしましたが、domain.ErrConflict ではなく %v が返りました", err)
+	}
+	if user.Version != 1 {
+		t.Fatalf("保存に失敗した場合はバージョンを更新しないはずですが、%d に更新されました", user.Version)
+	}
+
+	// 新しいユーザーとして保存し直すこともできない
+	user.Version = 0
+	if err := repo.Put(ctx, &user); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("保存済みのユーザーをバージョン 0 で保存しようとしましたが、domain.ErrConflict ではなく %v が返りました", err)
+	}
+
+	gotUser, err := repo.Get(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("保存したユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	if gotUser.Name != "ユーザーB" || gotUser.Version != 2 {
+		t.Fatalf("保存に失敗したユーザーで上書きされています: %+v", *gotUser)
+	}
+}
+
+// List のテスト。
+func testList(t *testing.T, repo domain.UserRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	t.Run("ユーザーが１人も保存されていない場合のテスト", func(t *testing.T) {
+		gotUsers, lastEvaluatedKey, err := repo.List(ctx, "", -1)
+		if err != nil {
+			t.Fatalf("ユーザーの一覧を取得しようとしましたが、エラーが発生しました: %v", err)
+		}
+		if len(gotUsers) != 0 {
+			t.Fatalf("ユーザーが１人も保存されていないはずなのに、ユーザーの一覧取得で以下のユーザーが取得されました: %+v", gotUsers)
+		}
+		if lastEvaluatedKey != "" {
+			t.Fatalf("ユーザーが１人も保存されていないはずなのに、ユーザーの一覧取得で空でない lastEvaluatedKey (%q) が返却されました", lastEvaluatedKey)
+		}
+	})
+
+	// ユーザーを 100 人保存
+	users := []domain.User{}
+	for i := 0; i < 100; i++ {
+		user := domain.User{
+			UserID:       fmt.Sprintf("U%d", i),
+			Name:         "ユーザー",
+			Status:       domain.UserStatusNormal,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}
+		if err := repo.Put(ctx, &user); err != nil {
+			t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+		}
+		// 保存後のバージョンのユーザーと比較する
+		users = append(users, user)
+	}
+
+	// スライスをソートしてから比較するためのオプション
+	sortUsers := cmpopts.SortSlices(func(x, y domain.User) bool { return x.UserID < y.UserID })
+
+	t.Run("ユーザーを全件取得するテスト", func(t *testing.T) {
+		testCases := []struct {
+			limit int
+		}{
+			{limit: 0},
+			{limit: -1},
+			{limit: -2},
+		}
+		for _, tc := range testCases {
+			t.Run(fmt.Sprintf("limit=%d", tc.limit), func(t *testing.T) {
+				gotUsers, lastEvaluatedKey, err := repo.List(ctx, "", tc.limit)
+				if err != nil {
+					t.Fatalf("ユーザーの一覧を取得しようとしましたが、エラーが発生しました: %v", err)
+				}
+				if diff := cmp.Diff(users, gotUsers, sortUsers); diff != "" {
+					t.Fatalf("保存したユーザー群 (-) と取得したユーザー群 (+) が一致しませんでした:\n%s", diff)
+				}
+				if lastEvaluatedKey != "" {
+					t.Fatalf("ユーザーを全件取得したはずなのに、空文字列でない lastEvaluatedKey (%q) が返却されました", lastEvaluatedKey)
+				}
+			})
+		}
+	})
+
+	t.Run("ページネーションのテスト", func(t *testing.T) {
+		// 100 件を割り切れる limit と割り切れない limit、ちょうど全件の limit でテスト
+		for _, limit := range []int{10, 30, 100} {
+			t.Run(fmt.Sprintf("limit=%d", limit), func(t *testing.T) {
+				gotAllUsers := []domain.User{}
+				exclusiveStartKey := ""
+
+				for {
+					gotUsers, lastEvaluatedKey, err := repo.List(ctx, exclusiveStartKey, limit)
+					if err != nil {
+						t.Fatalf("ユーザーの一覧を取得しようとしましたが、エラーが発生しました: %v", err)
+					}
+					if len(gotUsers) > limit {
+						t.Fatalf("ユーザーを最大 %d 件取得しようとしましたが、%d 件取得されました", limit, len(gotUsers))
+					}
+					gotAllUsers = append(gotAllUsers, gotUsers...)
+
+					if lastEvaluatedKey == "" {
+						break
+					} else {
+						exclusiveStartKey = lastEvaluatedKey
+					}
+
+					if len(gotAllUsers) > len(users) {
+						t.Fatalf("保存したユーザーの人数 (%d) より多くのユーザーが取得されました", len(users))
+					}
+				}
+
+				if diff := cmp.Diff(users, gotAllUsers, sortUsers); diff != "" {
+					t.Fatalf("保存したユーザー群 (-) と取得したユーザー群 (+) が一致しませんでした:\n%s", diff)
+				}
+			})
+		}
+	})
+
+	t.Run("ユーザー ID の昇順に取得されることのテスト", func(t *testing.T) {
+		gotUsers, _, err := repo.List(ctx, "", -1)
+		if err != nil {
+			t.Fatalf("ユーザーの一覧を取得しようとしましたが、エラーが発生しました: %v", err)
+		}
+		for i := 1; i < len(gotUsers); i++ {
+			if gotUsers[i-1].UserID >= gotUsers[i].UserID {
+				t.Fatalf("ユーザーがユーザー ID の昇順に並んでいません: %q, %q", gotUsers[i-1].UserID, gotUsers[i].UserID)
+			}
+		}
+	})
+
+	t.Run("最後のユーザーの ID を exclusiveStartKey に指定した場合のテスト", func(t *testing.T) {
+		// ユーザー ID の昇順で最後のユーザーは U99
+		gotUsers, lastEvaluatedKey, err := repo.List(ctx, "U99", 10)
+		if err != nil {
+			t.Fatalf("ユーザーの一覧を取得しようとしましたが、エラーが発生しました: %v", err)
+		}
+		if len(gotUsers) != 0 {
+			t.Fatalf("最後のユーザーより後にはユーザーがいないはずなのに、以下のユーザーが取得されました: %+v", gotUsers)
+		}
+		if lastEvaluatedKey != "" {
+			t.Fatalf("最後のユーザーより後にはユーザーがいないはずなのに、空文字列でない lastEvaluatedKey (%q) が返却されました", lastEvaluatedKey)
+		}
+	})
+}
+
+// Delete のテスト。
+func testDelete(t *testing.T, repo domain.UserRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	user := domain.User{
+		UserID:       "U1",
+		Name:         "ユーザー1",
+		Status:       domain.UserStatusNormal,
+		RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// 保存済みのユーザーを用意
+	if err := repo.Put(ctx, &user); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+	if _, err := repo.Get(ctx, user.UserID); err != nil {
+		t.Fatalf("保存したユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+
+	// ユーザーを削除
+	deleted, err := repo.Delete(ctx, user.UserID, testDeletedAt)
+	if err != nil {
+		t.Fatalf("ユーザーの削除に失敗しました: %v", err)
+	}
+	if !deleted {
+		t.Fatalf("保存済みのユーザーを削除しましたが、deleted が false でした")
+	}
+
+	// ユーザーが削除されたことを確認
+	_, err = repo.Get(ctx, user.UserID)
+	if err == nil {
+		t.Fatalf("削除済みのユーザーを取得しようとしましたが、エラーが発生しませんでした")
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("削除済みのユーザーを取得しようとしましたが、予期しないエラーが発生しました: %v", err)
+	}
+
+	// 削除済みのユーザーは GetIncludingDeleted で取得できることを確認
+	deletedUser, err := repo.GetIncludingDeleted(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("削除済みのユーザーを GetIncludingDeleted で取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	if !deletedUser.IsDeleted() {
+		t.Fatalf("削除済みのユーザーの削除日時が設定されていません: %+v", *deletedUser)
+	}
+	if deletedUser.Version != user.Version+1 {
+		t.Fatalf("削除するとバージョンが %d になるはずですが、%d でした", user.Version+1, deletedUser.Version)
+	}
+	if !deletedUser.DeletedAt.Equal(testDeletedAt) || !deletedUser.UpdatedAt.Equal(testDeletedAt) {
+		t.Fatalf("削除すると削除日時と最終更新日時は指定した日時 %v になるはずですが、%v, %v でした", testDeletedAt, *deletedUser.DeletedAt, deletedUser.UpdatedAt)
+	}
+
+	// 削除前に取得したユーザーは（古いバージョンなので）保存できないことを確認
+	if err := repo.Put(ctx, &user); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("削除前に取得したユーザーを保存しようとしましたが、domain.ErrConflict ではなく %v が返りました", err)
+	}
+}
+
+// Delete が冪等である、つまりすでに削除されているユーザーを削除しようとしても何もしないことのテスト。
+func testDeleteIdempotency(t *testing.T, repo domain.UserRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	user := domain.User{
+		UserID:       "U1",
+		Name:         "ユーザー1",
+		Status:       domain.UserStatusNormal,
+		RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// 保存済みのユーザーを用意
+	if err := repo.Put(ctx, &user); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+	if _, err := repo.Get(ctx, user.UserID); err != nil {
+		t.Fatalf("保存したユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+
+	// ユーザーを削除
+	if _, err := repo.Delete(ctx, user.UserID, testDeletedAt); err != nil {
+		t.Fatalf("ユーザーの削除に失敗しました: %v", err)
+	}
+
+	// ユーザーが削除されたことを確認
+	if _, err := repo.Get(ctx, user.UserID); err == nil {
+		t.Fatalf("削除済みのユーザーを取得しようとしましたが、エラーが発生しませんでした")
+	}
+
+	// 削除済みのユーザーを削除しようとしても問題ないことを確認
+	deleted, err := repo.Delete(ctx, user.UserID, testDeletedAt)
+	if err != nil {
+		t.Fatalf("削除済みのユーザーを削除しようとしたところ、エラーが発生しました: %v", err)
+	}
+	if deleted {
+		t.Fatalf("削除済みのユーザーを削除しましたが、deleted が true でした")
+	}
+
+	// 一度も保存されていないユーザーを削除しようとしても問題ないことを確認
+	deleted, err = repo.Delete(ctx, "U2", testDeletedAt)
+	if err != nil {
+		t.Fatalf("存在しないユーザーを削除しようとしたところ、エラーが発生しました: %v", err)
+	}
+	if deleted {
+		t.Fatalf("存在しないユーザーを削除しましたが、deleted が true でした")
+	}
+}
+
+// List が削除済みのユーザーを含まないことのテスト。
+func testListExcludesDeletedUsers(t *testing.T, repo domain.UserRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	users := []domain.User{}
+	for i := 1; i <= 5; i++ {
+		user := domain.User{
+			UserID:       fmt.Sprintf("U%d", i),
+			Name:         "ユーザー",
+			Status:       domain.UserStatusNormal,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}
+		if err := repo.Put(ctx, &user); err != nil {
+			t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+		}
+		users = append(users, user)
+	}
+
+	// U2 と、ユーザー ID の昇順で最後の U5 を削除
+	for _, userID := range []string{"U2", "U5"} {
+		if _, err := repo.Delete(ctx, userID, testDeletedAt); err != nil {
+			t.Fatalf("ユーザーの削除に失敗しました: %v", err)
+		}
+	}
+	wantUsers := []domain.User{users[0], users[2], users[3]}
+
+	// 削除済みのユーザーが最後にある場合も、続きがないと判定されることを確認するため、ちょうど残りの人数を limit にする
+	for _, limit := range []int{-1, 1, 3} {
+		t.Run(fmt.Sprintf("limit=%d", limit), func(t *testing.T) {
+			gotAllUsers := []domain.User{}
+			exclusiveStartKey := ""
+
+			for {
+				gotUsers, lastEvaluatedKey, err := repo.List(ctx, exclusiveStartKey, limit)
+				if err != nil {
+					t.Fatalf("ユーザーの一覧を取得しようとしましたが、エラーが発生しました: %v", err)
+				}
+				gotAllUsers = append(gotAllUsers, gotUsers...)
+
+				if lastEvaluatedKey == "" {
+					break
+				}
+				exclusiveStartKey = lastEvaluatedKey
+
+				if len(gotAllUsers) > len(users) {
+					t.Fatalf("保存したユーザーの人数 (%d) より多くのユーザーが取得されました", len(users))
+				}
+			}
+
+			if diff := cmp.Diff(wantUsers, gotAllUsers); diff != "" {
+				t.Fatalf("削除されていないユーザー群 (-) と取得したユーザー群 (+) が一致しませんでした:\n%s", diff)
+			}
+		})
+	}
+}
+
+// 削除済みのユーザーを GetIncludingDeleted で取得し、復元して保存できることのテスト。
+func testRestore(t *testing.T, repo domain.UserRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	user := domain.User{
+		UserID:       "U1",
+		Name:         "ユーザー1",
+		Status:       domain.UserStatusNormal,
+		RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := repo.Put(ctx, &user); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+	if _, err := repo.Delete(ctx, user.UserID, testDeletedAt); err != nil {
+		t.Fatalf("ユーザーの削除に失敗しました: %v", err)
+	}
+
+	deletedUser, err := repo.GetIncludingDeleted(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("削除済みのユーザーを GetIncludingDeleted で取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	deletedUser.Restore(domain.NewFakeClock(testDeletedAt.Add(1 * time.Hour)))
+	if err := repo.Put(ctx, deletedUser); err != nil {
+		t.Fatalf("復元したユーザーの保存に失敗しました: %v", err)
+	}
+
+	// 復元したユーザーが Get と List で取得されることを確認
+	gotUser, err := repo.Get(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("復元したユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	if diff := cmp.Diff(*deletedUser, *gotUser); diff != "" {
+		t.Fatalf("復元したユーザー (-) と取得したユーザー (+) が一致しませんでした:\n%s", diff)
+	}
+	gotUsers, _, err := repo.List(ctx, "", -1)
+	if err != nil {
+		t.Fatalf("ユーザーの一覧を取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	if len(gotUsers) != 1 || gotUsers[0].UserID != user.UserID {
+		t.Fatalf("復元したユーザーだけが取得されるはずですが、以下のユーザーが取得されました: %+v", gotUsers)
+	}
+
+	// 復元したユーザーを再び削除できることを確認
+	deleted, err := repo.Delete(ctx, user.UserID, testDeletedAt)
+	if err != nil {
+		t.Fatalf("ユーザーの削除に失敗しました: %v", err)
+	}
+	if !deleted {
+		t.Fatalf("復元したユーザーを削除しましたが、deleted が false でした")
+	}
+}
+
+// Purge が、削除日時が指定した日時より前の削除済みのユーザーのみを完全に削除することのテスト。
+func testPurge(t *testing.T, repo domain.UserRepository) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	// U1 は削除済み、U2 は削除されていないユーザー
+	for _, userID := range []string{"U1", "U2"} {
+		user := domain.User{
+			UserID:       userID,
+			Name:         "ユーザー",
+			Status:       domain.UserStatusNormal,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}
+		if err := repo.Put(ctx, &user); err != nil {
+			t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+		}
+	}
+	if _, err := repo.Delete(ctx, "U1", testDeletedAt); err != nil {
+		t.Fatalf("ユーザーの削除に失敗しました: %v", err)
+	}
+
+	// 削除日時より前を指定した場合は、完全に削除されない
+	purged, err := repo.Purge(ctx, testDeletedAt.Add(-1*time.Hour))
+	if err != nil {
+		t.Fatalf("削除済みのユーザーの完全削除に失敗しました: %v", err)
+	}
+	if purged != 0 {
+		t.Fatalf("削除日時より前を指定したので完全に削除されるユーザーはいないはずですが、%d 人が完全に削除されました", purged)
+	}
+	if _, err := repo.GetIncludingDeleted(ctx, "U1"); err != nil {
+		t.Fatalf("完全に削除されていないはずの削除済みのユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+
+	// 削除日時より後を指定した場合は、削除済みのユーザーのみ完全に削除される
+	purged, err = repo.Purge(ctx, testDeletedAt.Add(1*time.Hour))
+	if err != nil {
+		t.Fatalf("削除済みのユーザーの完全削除に失敗しました: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("削除済みのユーザー１人が完全に削除されるはずですが、%d 人が完全に削除されました", purged)
+	}
+	if _, err := repo.GetIncludingDeleted(ctx, "U1"); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("完全に削除したユーザーを取得しようとしましたが、domain.ErrUserNotFound ではなく %v が返りました", err)
+	}
+	if _, err := repo.Get(ctx, "U2"); err != nil {
+		t.Fatalf("削除されていないユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+
+	// 完全に削除したユーザーは削除できない（見つからない）ことを確認
+	deleted, err := repo.Delete(ctx, "U1", testDeletedAt)
+	if err != nil {
+		t.Fatalf("完全に削除したユーザーを削除しようとしたところ、エラーが発生しました: %v", err)
+	}
+	if deleted {
+		t.Fatalf("完全に削除したユーザーを削除しましたが、deleted が true でした")
+	}
+}
+
+// ListExpiredFrozen が、凍結の期限が指定した日時以前の凍結状態のユーザーのみを取得することのテスト。
+func testListExpiredFrozen(t *testing.T, repo domain.UserRepository) {
+	now := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	before := now.Add(-1 * time.Hour)
+	after := now.Add(1 * time.Hour)
+
+	testListExpired(t, repo, repo.ListExpiredFrozen, now, []expiredTestCase{
+		{domain.User{UserID: "U1", Status: domain.UserStatusFrozen, FrozenUntil: &before}, false, true}, // 期限が過ぎている
+		{domain.User{UserID: "U2", Status: domain.UserStatusFrozen, FrozenUntil: &now}, false, true},    // ちょうど期限
+		{domain.User{UserID: "U3", Status: domain.UserStatusFrozen, FrozenUntil: &after}, false, false}, // 期限前
+		{domain.User{UserID: "U4", Status: domain.UserStatusFrozen}, false, false},                      // 無期限
+		{domain.User{UserID: "U5", Status: domain.UserStatusSuspended, SuspendedUntil: &before}, false, false},
+		{domain.User{UserID: "U6", Status: domain.UserStatusFrozen, FrozenUntil: &before}, true, false}, // 削除済み
+		{domain.User{UserID: "U7", Status: domain.UserStatusFrozen, FrozenUntil: &before}, false, true}, // 期限が過ぎている
+	})
+}
+
+// testListExpired のテストケース。
+type expiredTestCase struct {
+	user    domain.User // 保存するユーザー
+	deleted bool        // 保存後に削除するかどうか
+	want    bool        // 取得されるべきかどうか
+}
+
+// 期限が過ぎたユーザーの一覧を取得するメソッド（ListExpiredFrozen）。
+type listExpiredFunc func(ctx context.Context, now time.Time, exclusiveStartKey string, limit int) ([]domain.User, string, error)
+
+// テストケースのユーザーを保存し、listExpired で now において期限が過ぎたユーザーのみを、ページネーションしながら取得できることをテストします。
+func testListExpired(t *testing.T, repo domain.UserRepository, listExpired listExpiredFunc, now time.Time, testCases []expiredTestCase) {
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	wantUsers := []domain.User{}
+	for _, tc := range testCases {
+		user := tc.user
+		user.Name = "ユーザー"
+		user.RegisteredAt = time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC)
+		if err := repo.Put(ctx, &user); err != nil {
+			t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+		}
+		if tc.deleted {
+			if _, err := repo.Delete(ctx, user.UserID, testDeletedAt); err != nil {
+				t.Fatalf("ユーザーの削除に失敗しました: %v", err)
+			}
+		}
+		if tc.want {
+			wantUsers = append(wantUsers, user)
+		}
+	}
+
+	// ちょうど取得される人数を limit にした場合も、続きがないと判定されることを確認する
+	for _, limit := range []int{-1, 1, len(wantUsers)} {
+		t.Run(fmt.Sprintf("limit=%d", limit), func(t *testing.T) {
+			gotAllUsers := []domain.User{}
+			exclusiveStartKey := ""
+
+			for {
+				gotUsers, lastEvaluatedKey, err := listExpired(ctx, now, exclusiveStartKey, limit)
+				if err != nil {
+					t.Fatalf("期限が過ぎたユーザーの一覧を取得しようとしましたが、エラーが発生しました: %v", err)
+				}
+				gotAllUsers = append(gotAllUsers, gotUsers...)
+
+				if lastEvaluatedKey == "" {
+					break
+				}
+				exclusiveStartKey = lastEvaluatedKey
+
+				if len(gotAllUsers) > len(testCases) {
+					t.Fatalf("保存したユーザーの人数 (%d) より多くのユーザーが取得されました", len(testCases))
+				}
+			}
+
+			if diff := cmp.Diff(wantUsers, gotAllUsers); diff != "" {
+				t.Fatalf("期限が過ぎたユーザー群 (-) と取得したユーザー群 (+) が一致しませんでした:\n%s", diff)
+			}
+		})
+	}
+}
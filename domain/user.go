@@ -9,29 +9,44 @@ import (
 	"github.com/google/uuid"
 )
 
-// ユーザーのステータス。
-type UserStatus string
-
-const (
-	UserStatusNormal UserStatus = "normal" // 通常。
-	UserStatusFrozen UserStatus = "frozen" // 凍結状態。
-)
+// ユーザーのステータス変更の記録。
+type UserStatusChange struct {
+	OperatorID string    // ステータスを変更した操作者の ID。
+	Reason     string    // ステータスを変更した理由。
+	ChangedAt  time.Time // ステータスの変更日時 (UTC)。
+}
 
 // ユーザー。システムの利用者です。
+//
+// ユーザーを変更するメソッドは、実際に変更した場合に changed として true を返します。
+// false の場合は何も変更していないので、保存しないでください（保存するとバージョンだけが変わり、ETag が変わってしまいます）。
 type User struct {
-	UserID       string     // ユーザー ID。
-	Name         string     // 名前。
-	Status       UserStatus // ステータス。
-	RegisteredAt time.Time  // 登録日時 (UTC)。
+	UserID           string            // ユーザー ID。
+	Name             string            // 名前。
+	Status           UserStatus        // ステータス。
+	RegisteredAt     time.Time         // 登録日時 (UTC)。
+	LastStatusChange *UserStatusChange // 最後のステータス変更の記録。ステータスが一度も変更されていない場合は nil です。
+	Version          int64             // バージョン。保存される毎に 1 増えます。一度も保存されていないユーザーは 0 です。
+	UpdatedAt        time.Time         // 最終更新日時 (UTC)。名前やステータスを変更した日時で、一度も変更されていない場合は登録日時です。
+	DeletedAt        *time.Time        // 削除日時 (UTC)。削除されていない場合は nil です。
+	SuspendedUntil   *time.Time        // 利用停止の期限 (UTC)。ステータスが suspended の場合のみ設定され、それ以外の場合は nil です。
+	FrozenUntil      *time.Time        // 凍結の期限 (UTC)。期限付きで凍結されている場合のみ設定され、無期限の凍結やステータスが frozen でない場合は nil です。
 }
 
-// 新しいユーザーを作成します。
-func NewUser(name string) User {
+// 新しいユーザーを作成します。登録日時と最終更新日時は clock の現在日時です。
+func NewUser(name string, clock Clock) User {
+	now := clock.Now()
 	return User{
 		uuid.New().String(),
 		name,
 		UserStatusNormal,
-		time.Now().UTC(),
+		now,
+		nil,
+		0,
+		now,
+		nil,
+		nil,
+		nil,
 	}
 }
 
@@ -42,43 +57,235 @@ func DummyUser(*testing.T) User {
 		"",
 		UserStatusNormal,
 		time.Unix(0, 0).UTC(),
+		nil,
+		0,
+		time.Unix(0, 0).UTC(),
+		nil,
+		nil,
+		nil,
+	}
+}
+
+// ユーザーが clock の現在日時において凍結状態であれば true、そうでなければ false を返します。
+// 凍結の期限が過ぎている場合は、ステータスが frozen のままでも凍結状態ではないものとして false を返します。
+func (user *User) IsFrozen(clock Clock) bool {
+	if user.Status != UserStatusFrozen {
+		return false
+	}
+	return user.FrozenUntil == nil || clock.Now().Before(*user.FrozenUntil)
+}
+
+// clock の現在日時におけるユーザーのステータスを返します。
+// 凍結や利用停止の期限が過ぎている場合は、保存されているステータスが frozen や suspended のままでも（ジョブが戻す前でも）normal を返します。
+// それ以外の場合は、保存されているステータスを返します。
+func (user *User) StatusAt(clock Clock) UserStatus {
+	switch user.Status {
+	case UserStatusFrozen:
+		if !user.IsFrozen(clock) {
+			return UserStatusNormal
+		}
+	case UserStatusSuspended:
+		if !user.IsSuspended(clock) {
+			return UserStatusNormal
+		}
+	}
+	return user.Status
+}
+
+// ユーザーが削除済みであれば true、そうでなければ false を返します。
+func (user *User) IsDeleted() bool {
+	return user.DeletedAt != nil
+}
+
+// ユーザーを論理削除します。つまり、削除日時と最終更新日時を clock の現在日時にします。
+// ユーザーが既に削除済みの場合は何もしません。ユーザーを変更した場合は true を返します。
+// 保存は UserRepository.Put で行うため、取得してから保存するまでの間の他の更新を検出できます
+// （UserRepository.Delete は、ユーザーの状態によらず削除します）。
+func (user *User) Delete(clock Clock) (changed bool) {
+	if user.IsDeleted() {
+		return false
+	}
+	now := clock.Now()
+	user.DeletedAt = &now
+	user.UpdatedAt = now
+	return true
+}
+
+// 削除済みのユーザーを復元し、最終更新日時を clock の現在日時にします。
+// ユーザーが削除済みでない場合は何もしません。ユーザーを変更した場合は true を返します。
+func (user *User) Restore(clock Clock) (changed bool) {
+	if !user.IsDeleted() {
+		return false
+	}
+	user.DeletedAt = nil
+	user.UpdatedAt = clock.Now()
+	return true
+}
+
+// ユーザーが clock の現在日時において利用停止状態であれば true、そうでなければ false を返します。
+// 利用停止の期限が過ぎている場合は、ステータスが suspended のままでも利用停止状態ではないものとして false を返します。
+func (user *User) IsSuspended(clock Clock) bool {
+	if user.Status != UserStatusSuspended {
+		return false
+	}
+	return user.SuspendedUntil == nil || clock.Now().Before(*user.SuspendedUntil)
+}
+
+// ユーザーが退会済みであれば true、そうでなければ false を返します。
+func (user *User) IsClosed() bool {
+	return user.Status == UserStatusClosed
+}
+
+// 本人確認待ちのユーザーを通常状態にし、操作者の ID と理由をステータス変更の記録として残します。
+// 本人確認待ち以外のユーザー（既に通常状態のユーザーを含む）の場合は、何も変更せずに *StatusTransitionError を返します。
+// 遷移表では凍結状態や利用停止状態からも通常状態に変更できますが、それらの解除には Unfreeze や LiftSuspension を使用してください。
+func (user *User) Activate(operatorID string, reason string, clock Clock) (changed bool, err error) {
+	if user.Status != UserStatusPending {
+		return false, &StatusTransitionError{From: user.Status, To: UserStatusNormal}
+	}
+	return user.changeStatus(UserStatusNormal, operatorID, reason, clock)
+}
+
+// ユーザーを凍結状態にし、操作者の ID と理由をステータス変更の記録として残します。
+// until を指定するとその日時に凍結の期限が切れ、nil の場合は無期限に凍結します。
+// ユーザーのステータスが既に frozen の場合は、期限を until に変更します（期限が変わらない場合は何もしません）。
+// until が clock の現在日時より後でない場合は、何も変更せずに ErrFreezeUntilNotFuture を返します。
+// 遷移表で許可されていない場合は、*StatusTransitionError を返します。
+func (user *User) Freeze(operatorID string, reason string, until *time.Time, clock Clock) (changed bool, err error) {
+	if until != nil {
+		if !until.After(clock.Now()) {
+			return false, ErrFreezeUntilNotFuture
+		}
+		utc := until.UTC()
+		until = &utc
 	}
+	if user.Status == UserStatusFrozen {
+		if equalTimePtr(user.FrozenUntil, until) {
+			return false, nil
+		}
+		user.recordStatusChange(operatorID, reason, clock)
+	} else if _, err := user.changeStatus(UserStatusFrozen, operatorID, reason, clock); err != nil {
+		return false, err
+	}
+	user.FrozenUntil = until
+	return true, nil
+}
+
+// ユーザーの凍結状態を解除し、操作者の ID と理由をステータス変更の記録として残します。
+// 凍結の期限が過ぎていても、ステータスが frozen であれば解除します。
+// ユーザーのステータスが frozen でない場合は何もしません（ステータス変更の記録も更新しません）。
+func (user *User) Unfreeze(operatorID string, reason string, clock Clock) (changed bool, err error) {
+	if user.Status != UserStatusFrozen {
+		return false, nil
+	}
+	return user.changeStatus(UserStatusNormal, operatorID, reason, clock)
 }
 
-// ユーザーが凍結状態であれば true、そうでなければ false を返します。
-func (user *User) IsFrozen() bool {
-	return user.Status == UserStatusFrozen
+// ユーザーを until まで利用停止状態にし、操作者の ID と理由をステータス変更の記録として残します。
+// ユーザーのステータスが既に suspended の場合は、期限を until に変更します（期限が変わらない場合は何もしません）。
+// until が clock の現在日時より後でない場合は、何も変更せずに ErrSuspensionUntilNotFuture を返します。
+// 遷移表で許可されていない場合は、*StatusTransitionError を返します。
+func (user *User) Suspend(until time.Time, operatorID string, reason string, clock Clock) (changed bool, err error) {
+	until = until.UTC()
+	if !until.After(clock.Now()) {
+		return false, ErrSuspensionUntilNotFuture
+	}
+	if user.Status == UserStatusSuspended {
+		if equalTimePtr(user.SuspendedUntil, &until) {
+			return false, nil
+		}
+		user.recordStatusChange(operatorID, reason, clock)
+	} else if _, err := user.changeStatus(UserStatusSuspended, operatorID, reason, clock); err != nil {
+		return false, err
+	}
+	user.SuspendedUntil = &until
+	return true, nil
 }
 
-// ユーザーを凍結状態にします。ユーザーが既に凍結状態の場合は何もしません。
-func (user *User) Freeze() {
-	user.Status = UserStatusFrozen
+// ユーザーの利用停止状態を解除し、操作者の ID と理由をステータス変更の記録として残します。
+// 利用停止の期限が過ぎていても、ステータスが suspended であれば解除します。
+// ユーザーのステータスが suspended でない場合は何もしません（ステータス変更の記録も更新しません）。
+func (user *User) LiftSuspension(operatorID string, reason string, clock Clock) (changed bool, err error) {
+	if user.Status != UserStatusSuspended {
+		return false, nil
+	}
+	return user.changeStatus(UserStatusNormal, operatorID, reason, clock)
 }
 
-// ユーザーの凍結状態を解除します。ユーザーが凍結状態でない場合は何もしません。
-func (user *User) Unfreeze() {
-	user.Status = UserStatusNormal
+// ユーザーを退会済みにし、操作者の ID と理由をステータス変更の記録として残します。
+// 退会済みのユーザーは、他のステータスに変更できません。
+// ユーザーが既に退会済みの場合は何もしません（ステータス変更の記録も更新しません）。
+func (user *User) Close(operatorID string, reason string, clock Clock) (changed bool, err error) {
+	if user.IsClosed() {
+		return false, nil
+	}
+	return user.changeStatus(UserStatusClosed, operatorID, reason, clock)
 }
 
-// ユーザーの名前を変更します。凍結状態のユーザーは名前を変更できません。
-// 凍結状態のユーザーの名前を変更しようとした場合、エラーを返します。
-// ユーザーが凍結状態でない場合は、エラーを返しません。
-func (user *User) ChangeName(name string) error {
-	if user.IsFrozen() {
-		return errors.New("凍結状態のユーザーは名前を変更できません。")
+// 遷移表に従ってユーザーのステータスを to に変更し、clock の現在日時でステータス変更の記録を残します。
+// 許可されていない変更の場合は、何も変更せずに *StatusTransitionError を返します。
+func (user *User) changeStatus(to UserStatus, operatorID string, reason string, clock Clock) (changed bool, err error) {
+	if !user.Status.CanTransitionTo(to) {
+		return false, &StatusTransitionError{From: user.Status, To: to}
+	}
+	user.Status = to
+	// 利用停止状態や凍結状態でなくなれば、期限も不要になる
+	user.SuspendedUntil = nil
+	user.FrozenUntil = nil
+	user.recordStatusChange(operatorID, reason, clock)
+	return true, nil
+}
+
+// ステータス変更の記録を残し、最終更新日時を更新します。変更日時と最終更新日時は clock の現在日時です。
+func (user *User) recordStatusChange(operatorID string, reason string, clock Clock) {
+	now := clock.Now()
+	user.LastStatusChange = &UserStatusChange{
+		OperatorID: operatorID,
+		Reason:     reason,
+		ChangedAt:  now,
+	}
+	user.UpdatedAt = now
+}
+
+// 2 つの日時がどちらも nil か、同じ日時を指していれば true を返します。
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// ユーザーの名前を変更します。凍結状態や退会済みのユーザーは名前を変更できません。
+// 凍結状態のユーザーの名前を変更しようとした場合は ErrUserFrozen を、退会済みのユーザーの場合は ErrUserClosed を返します。
+// それ以外（凍結の期限が過ぎている場合を含む）の場合は、エラーを返しません。
+// 名前が変わらない場合は何もしません。ユーザーを変更した場合は true を返し、最終更新日時を clock の現在日時にします。
+func (user *User) ChangeName(name string, clock Clock) (changed bool, err error) {
+	if user.Status == UserStatusClosed {
+		return false, ErrUserClosed
+	}
+	if user.IsFrozen(clock) {
+		return false, ErrUserFrozen
+	}
+	if user.Name == name {
+		return false, nil
 	}
 
 	user.Name = name
-	return nil
+	user.UpdatedAt = clock.Now()
+	return true, nil
 }
 
 // ユーザーのリポジトリ。
 type UserRepository interface {
 	// ユーザーを取得します。
-	// ユーザーが見つからない場合は ErrUserNotFound を返します。
+	// ユーザーが見つからない場合は ErrUserNotFound を返します。削除済みのユーザーも見つからないものとして扱います。
 	Get(ctx context.Context, userID string) (*User, error)
 
-	// ユーザー一覧を取得します。
+	// 削除済みのユーザーも含めてユーザーを取得します。
+	// ユーザーが見つからない場合（完全に削除された場合を含む）は ErrUserNotFound を返します。
+	GetIncludingDeleted(ctx context.Context, userID string) (*User, error)
+
+	// ユーザー一覧を取得します。削除済みのユーザーは含みません。
 	//
 	// 初回の呼び出しでは exclusiveStartKey に空文字を指定してください。
 	// 戻り値の lastEvaluatedKey が空文字列でない場合、
@@ -92,15 +299,49 @@ type UserRepository interface {
 	// limit に 0 または負数を指定すると、制限なし（最大取得件数が無限大）となります。この場合は、全件取得されます。
 	List(ctx context.Context, exclusiveStartKey string, limit int) (users []User, lastEvaluatedKey string, err error)
 
+	// 凍結の期限が now 以前の（now において凍結の期限が過ぎている）、ステータスが frozen のユーザーの一覧を取得します。
+	// 無期限に凍結されたユーザーや、削除済みのユーザーは含みません。
+	// ユーザー ID の昇順に返し、exclusiveStartKey, limit, lastEvaluatedKey の扱いは List と同じです。
+	ListExpiredFrozen(ctx context.Context, now time.Time, exclusiveStartKey string, limit int) (users []User, lastEvaluatedKey string, err error)
+
 	// ユーザーを保存します。
+	//
+	// 保存は楽観的排他制御により行います。つまり、user.Version が保存されているユーザーのバージョン
+	// （保存されていない場合は 0）と一致する場合のみ保存し、一致しない場合は保存せずに ErrConflict を返します。
+	// 保存に成功した場合は、user.Version を保存後のバージョン（保存前のバージョン + 1）に更新します。
 	Put(ctx context.Context, user *User) error
 
 	// ユーザーを削除します。
-	// この操作は冪等です。つまり、ユーザーが見つからない場合は何もしません（この場合、エラーは返しません）。
-	Delete(ctx context.Context, userID string) error
+	// 削除は論理削除です。つまり、ユーザーの削除日時（DeletedAt）と最終更新日時を deletedAt にし、バージョンを 1 増やします。
+	// deletedAt には、ユースケースの時計の現在日時を指定してください。
+	// 削除済みのユーザーは GetIncludingDeleted でのみ取得でき、Purge で完全に削除されるまでは復元できます。
+	//
+	// この操作は冪等です。つまり、ユーザーが見つからない（あるいは削除済みの）場合は何もしません（この場合、エラーは返しません）。
+	// 戻り値の deleted は、ユーザーが実際に削除された場合は true、ユーザーが見つからなかった場合は false です。
+	Delete(ctx context.Context, userID string, deletedAt time.Time) (deleted bool, err error)
+
+	// 削除日時が deletedBefore より前の削除済みのユーザーを、完全に削除します。
+	// 完全に削除したユーザーは、復元できません。
+	// 戻り値の purged は、完全に削除したユーザーの人数です。
+	Purge(ctx context.Context, deletedBefore time.Time) (purged int, err error)
 }
 
 var (
 	// ErrUserNotFound は、ユーザーが見つからなかったことを表します。
 	ErrUserNotFound = errors.New("ユーザーが見つかりません。")
+
+	// ErrUserFrozen は、凍結状態のユーザーに対して許可されていない操作を行おうとしたことを表します。
+	ErrUserFrozen = errors.New("凍結状態のユーザーには、この操作を行えません。")
+
+	// ErrUserClosed は、退会済みのユーザーに対して許可されていない操作を行おうとしたことを表します。
+	ErrUserClosed = errors.New("退会済みのユーザーには、この操作を行えません。")
+
+	// ErrFreezeUntilNotFuture は、凍結の期限に現在以前の日時を指定したことを表します。
+	ErrFreezeUntilNotFuture = errors.New("凍結の期限は、現在より後の日時でなければなりません。")
+
+	// ErrSuspensionUntilNotFuture は、利用停止の期限に現在以前の日時を指定したことを表します。
+	ErrSuspensionUntilNotFuture = errors.New("利用停止の期限は、現在より後の日時でなければなりません。")
+
+	// ErrConflict は、保存しようとしたユーザーが、取得してから保存するまでの間に他の操作によって更新（あるいは削除）されたことを表します。
+	ErrConflict = errors.New("ユーザーが他の操作によって更新されています。")
 )
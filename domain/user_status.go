@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ユーザーのステータス。
+type UserStatus string
+
+const (
+	UserStatusPending   UserStatus = "pending"   // 本人確認待ち。
+	UserStatusNormal    UserStatus = "normal"    // 通常。
+	UserStatusFrozen    UserStatus = "frozen"    // 凍結状態。
+	UserStatusSuspended UserStatus = "suspended" // 期限付きの利用停止状態。期限はユーザーの SuspendedUntil です。
+	UserStatusClosed    UserStatus = "closed"    // 退会済み。他のステータスには変更できません。
+)
+
+// ユーザーのステータスの遷移表。ステータス毎に、変更できるステータスの一覧を定義します。
+// ステータスを追加する場合は、ここに追加してください（[UserStatuses] もこの表から作られます）。
+var userStatusTransitions = []struct {
+	from UserStatus
+	to   []UserStatus
+}{
+	{UserStatusPending, []UserStatus{UserStatusNormal, UserStatusClosed}},
+	{UserStatusNormal, []UserStatus{UserStatusFrozen, UserStatusSuspended, UserStatusClosed}},
+	{UserStatusFrozen, []UserStatus{UserStatusNormal, UserStatusClosed}},
+	{UserStatusSuspended, []UserStatus{UserStatusNormal, UserStatusFrozen, UserStatusClosed}},
+	{UserStatusClosed, []UserStatus{}},
+}
+
+// すべてのユーザーのステータスを、遷移表の順に返します。
+func UserStatuses() []UserStatus {
+	statuses := make([]UserStatus, 0, len(userStatusTransitions))
+	for _, transition := range userStatusTransitions {
+		statuses = append(statuses, transition.from)
+	}
+	return statuses
+}
+
+// ステータスが遷移表で定義されていれば true、そうでなければ false を返します。
+func (status UserStatus) IsValid() bool {
+	for _, transition := range userStatusTransitions {
+		if transition.from == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ステータスを to に変更できれば true、そうでなければ false を返します。
+// 同じステータスへの変更は、遷移表に定義されていない限り false です。
+func (status UserStatus) CanTransitionTo(to UserStatus) bool {
+	for _, transition := range userStatusTransitions {
+		if transition.from != status {
+			continue
+		}
+		for _, allowed := range transition.to {
+			if allowed == to {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StatusTransitionError は、遷移表で許可されていないステータスの変更を行おうとしたことを表します。
+// errors.Is(err, ErrInvalidStatusTransition) で判定できます。
+type StatusTransitionError struct {
+	From UserStatus // 変更前のステータス。
+	To   UserStatus // 変更しようとしたステータス。
+}
+
+func (err *StatusTransitionError) Error() string {
+	return fmt.Sprintf("ユーザーのステータスを %s から %s に変更することはできません。", err.From, err.To)
+}
+
+func (err *StatusTransitionError) Is(target error) bool {
+	return target == ErrInvalidStatusTransition
+}
+
+// ErrInvalidStatusTransition は、許可されていないステータスの変更を行おうとしたことを表します。
+// 実際に返されるエラーは *StatusTransitionError です。
+var ErrInvalidStatusTransition = errors.New("許可されていないステータスの変更です。")
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// すべてのステータスが遷移表に定義されていることのテスト。
+func TestUserStatuses(t *testing.T) {
+	want := []UserStatus{UserStatusPending, UserStatusNormal, UserStatusFrozen, UserStatusSuspended, UserStatusClosed}
+	if diff := cmp.Diff(want, UserStatuses()); diff != "" {
+		t.Errorf("期待されるステータスの一覧 (-) と実際の一覧 (+) が一致しません:\n%s", diff)
+	}
+
+	for _, status := range UserStatuses() {
+		if !status.IsValid() {
+			t.Errorf("ステータス %q の IsValid メソッドが false を返しました", status)
+		}
+	}
+	if UserStatus("unknown").IsValid() {
+		t.Errorf("未定義のステータスの IsValid メソッドが true を返しました")
+	}
+}
+
+// 遷移表のテスト。
+func TestUserStatusCanTransitionTo(t *testing.T) {
+	// 許可されている遷移の一覧。ここにない遷移は許可されない。
+	allowed := map[UserStatus][]UserStatus{
+		UserStatusPending:   {UserStatusNormal, UserStatusClosed},
+		UserStatusNormal:    {UserStatusFrozen, UserStatusSuspended, UserStatusClosed},
+		UserStatusFrozen:    {UserStatusNormal, UserStatusClosed},
+		UserStatusSuspended: {UserStatusNormal, UserStatusFrozen, UserStatusClosed},
+		UserStatusClosed:    {},
+	}
+
+	statuses := append(UserStatuses(), UserStatus("unknown"))
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := false
+			for _, status := range allowed[from] {
+				if status == to {
+					want = true
+				}
+			}
+
+			t.Run(fmt.Sprintf("%s->%s", from, to), func(t *testing.T) {
+				if got := from.CanTransitionTo(to); got != want {
+					t.Errorf("%s から %s への遷移の可否は %t のはずですが、%t でした", from, to, want, got)
+				}
+			})
+		}
+	}
+}
+
+// StatusTransitionError が ErrInvalidStatusTransition として判定できることのテスト。
+func TestStatusTransitionError(t *testing.T) {
+	var err error = fmt.Errorf("ラップしたエラー: %w", &StatusTransitionError{From: UserStatusClosed, To: UserStatusNormal})
+
+	if !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("errors.Is(err, ErrInvalidStatusTransition) が false を返しました")
+	}
+	var transitionErr *StatusTransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("errors.As で *StatusTransitionError を取り出せませんでした")
+	}
+	if transitionErr.From != UserStatusClosed || transitionErr.To != UserStatusNormal {
+		t.Errorf("変更前後のステータスが正しくありません: %+v", *transitionErr)
+	}
+}
@@ -1,12 +1,17 @@
 package domain
 
-import "testing"
+import (
+	"errors"
+	"testing"
+	"time"
+)
 
 // 新しいユーザーを作成するテスト。
 func TestNewUser(t *testing.T) {
-	user := NewUser("name")
+	clock := NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	user := NewUser("name", clock)
 
-	anotherUser := NewUser("name")
+	anotherUser := NewUser("name", clock)
 	if user.UserID == anotherUser.UserID {
 		t.Errorf("ユーザーの ID は一意のはずですが、新しく作成した２ユーザーの ID が等しくなっています。"+
 			"１人目のユーザーの ID: %q, ２人目のユーザーの ID: %q", user.UserID, anotherUser.UserID)
@@ -19,30 +24,390 @@ func TestNewUser(t *testing.T) {
 	if user.Status != UserStatusNormal {
 		t.Errorf("新しいユーザーのステータスは %q のはずですが、設定されたステータスは %q です。", UserStatusNormal, user.Status)
 	}
+
+	if !user.RegisteredAt.Equal(clock.Now()) {
+		t.Errorf("新しいユーザーの登録日時は現在日時 %v のはずですが、%v です。", clock.Now(), user.RegisteredAt)
+	}
+
+	if !user.UpdatedAt.Equal(user.RegisteredAt) {
+		t.Errorf("新しいユーザーの最終更新日時は登録日時 %v のはずですが、%v です。", user.RegisteredAt, user.UpdatedAt)
+	}
 }
 
 // ユーザーの凍結・凍結解除のテスト。
 func TestFreezeUnfreeze(t *testing.T) {
-	user := NewUser("")
+	clock := NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	user := NewUser("", clock)
+	if user.LastStatusChange != nil {
+		t.Errorf("新しいユーザーのステータス変更の記録は nil のはずですが、%+v が設定されています。", *user.LastStatusChange)
+	}
 
-	user.Freeze()
-	if !user.IsFrozen() {
+	clock.Advance(time.Hour)
+	if changed, err := user.Freeze("OP1", "凍結理由", nil, clock); err != nil || !changed {
+		t.Fatalf("通常状態のユーザーに Freeze メソッドを実行しましたが、changed=%t, err=%v が返りました", changed, err)
+	}
+	if !user.IsFrozen(clock) {
 		t.Errorf("Freeze メソッドを実行しましたが、IsFrozen メソッドが false を返しました。")
 	}
+	if user.LastStatusChange == nil || user.LastStatusChange.OperatorID != "OP1" || user.LastStatusChange.Reason != "凍結理由" {
+		t.Errorf("Freeze メソッドを実行しましたが、ステータス変更の記録が正しく残されていません: %+v", user.LastStatusChange)
+	}
+	if !user.LastStatusChange.ChangedAt.Equal(clock.Now()) || !user.UpdatedAt.Equal(clock.Now()) {
+		t.Errorf("Freeze メソッドを実行しましたが、ステータスの変更日時 %v と最終更新日時 %v が現在日時 %v になっていません。", user.LastStatusChange.ChangedAt, user.UpdatedAt, clock.Now())
+	}
 
-	user.Unfreeze()
-	if user.IsFrozen() {
+	// 凍結状態のユーザーを再度凍結しても、何も変更されない
+	if changed, err := user.Freeze("OP2", "別の凍結理由", nil, clock); err != nil || changed {
+		t.Fatalf("凍結状態のユーザーに Freeze メソッドを実行しましたが、changed=%t, err=%v が返りました", changed, err)
+	}
+	if user.LastStatusChange.OperatorID != "OP1" {
+		t.Errorf("凍結状態のユーザーに Freeze メソッドを実行しましたが、ステータス変更の記録が更新されました: %+v", *user.LastStatusChange)
+	}
+
+	if _, err := user.Unfreeze("OP3", "凍結解除理由", clock); err != nil {
+		t.Fatalf("凍結状態のユーザーに Unfreeze メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if user.IsFrozen(clock) {
 		t.Errorf("Unfreeze メソッドを実行しましたが、IsFrozen メソッドが true を返しました。")
 	}
+	if user.LastStatusChange == nil || user.LastStatusChange.OperatorID != "OP3" || user.LastStatusChange.Reason != "凍結解除理由" {
+		t.Errorf("Unfreeze メソッドを実行しましたが、ステータス変更の記録が正しく残されていません: %+v", user.LastStatusChange)
+	}
+
+	// 凍結状態でないユーザーの凍結を解除しても、何も変更されない
+	if changed, err := user.Unfreeze("OP4", "凍結解除理由", clock); err != nil || changed {
+		t.Errorf("凍結状態でないユーザーに Unfreeze メソッドを実行しましたが、changed=%t, err=%v が返りました", changed, err)
+	}
+}
+
+// 期限付きの凍結のテスト。
+func TestTimedFreeze(t *testing.T) {
+	clock := NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	user := NewUser("", clock)
+	until := clock.Now().AddDate(0, 0, 7)
+
+	// 期限が現在以前の場合は、何も変更されずにエラーになる
+	for _, invalidUntil := range []time.Time{clock.Now().Add(-time.Nanosecond), clock.Now()} {
+		if changed, err := user.Freeze("OP0", "過去の期限", &invalidUntil, clock); !errors.Is(err, ErrFreezeUntilNotFuture) || changed {
+			t.Errorf("期限 %v で Freeze メソッドを実行しましたが、changed=%t, err=%v が返りました", invalidUntil, changed, err)
+		}
+	}
+	if user.Status != UserStatusNormal || user.LastStatusChange != nil {
+		t.Errorf("期限が不正な Freeze メソッドの実行で、ユーザーが変更されました: %+v", user)
+	}
+
+	if _, err := user.Freeze("OP1", "7 日間の凍結", &until, clock); err != nil {
+		t.Fatalf("通常状態のユーザーに期限付きで Freeze メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if user.FrozenUntil == nil || !user.FrozenUntil.Equal(until) {
+		t.Errorf("凍結の期限は %v のはずですが、%v が設定されています。", until, user.FrozenUntil)
+	}
+	if !user.IsFrozen(clock) {
+		t.Errorf("期限前ですが、IsFrozen メソッドが false を返しました。")
+	}
+
+	// 同じ期限で再度凍結しても、ステータス変更の記録は更新されない
+	if _, err := user.Freeze("OP2", "同じ期限", &until, clock); err != nil {
+		t.Fatalf("凍結状態のユーザーに Freeze メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if user.LastStatusChange.OperatorID != "OP1" {
+		t.Errorf("同じ期限で Freeze メソッドを実行しましたが、ステータス変更の記録が更新されました: %+v", *user.LastStatusChange)
+	}
+
+	// 期限の直前までは凍結状態で、期限を過ぎると凍結状態ではなくなる（ステータスは frozen のまま）
+	clock.Advance(7*24*time.Hour - time.Nanosecond)
+	if !user.IsFrozen(clock) {
+		t.Errorf("期限の直前ですが、IsFrozen メソッドが false を返しました。")
+	}
+	clock.Advance(time.Nanosecond)
+	if user.IsFrozen(clock) {
+		t.Errorf("期限を過ぎましたが、IsFrozen メソッドが true を返しました。")
+	}
+	if user.Status != UserStatusFrozen {
+		t.Errorf("期限を過ぎてもステータスは %q のままのはずですが、%q になっています。", UserStatusFrozen, user.Status)
+	}
+	if _, err := user.ChangeName("newname", clock); err != nil {
+		t.Errorf("凍結の期限が過ぎたユーザーの名前を変更しようとしましたが、エラーが発生しました: %v", err)
+	}
+
+	// 期限付きの凍結を無期限に変更すると、ステータス変更の記録が更新される
+	if _, err := user.Freeze("OP3", "無期限に変更", nil, clock); err != nil {
+		t.Fatalf("凍結状態のユーザーに無期限で Freeze メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if user.FrozenUntil != nil || user.LastStatusChange.OperatorID != "OP3" {
+		t.Errorf("無期限に変更しましたが、期限 %v やステータス変更の記録が正しくありません: %+v", user.FrozenUntil, *user.LastStatusChange)
+	}
+	if !user.IsFrozen(clock) {
+		t.Errorf("無期限に凍結しましたが、IsFrozen メソッドが false を返しました。")
+	}
+
+	// 凍結を解除すると、期限はなくなる
+	newUntil := clock.Now().AddDate(0, 0, 7)
+	if _, err := user.Freeze("OP4", "期限付きに変更", &newUntil, clock); err != nil {
+		t.Fatalf("凍結状態のユーザーに期限付きで Freeze メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if _, err := user.Unfreeze("OP5", "凍結解除理由", clock); err != nil {
+		t.Fatalf("期限の過ぎたユーザーに Unfreeze メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if user.Status != UserStatusNormal || user.FrozenUntil != nil {
+		t.Errorf("Unfreeze メソッドを実行しましたが、凍結状態が解除されていません: %+v", user)
+	}
+}
+
+// 期限を考慮したステータスのテスト。
+func TestStatusAt(t *testing.T) {
+	clock := NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	before := clock.Now().Add(-time.Hour)
+	after := clock.Now().Add(time.Hour)
+
+	testCases := []struct {
+		name string     // テストケース名
+		user User       // ユーザー
+		want UserStatus // 期待されるステータス
+	}{
+		{"通常状態", User{Status: UserStatusNormal}, UserStatusNormal},
+		{"期限前の凍結状態", User{Status: UserStatusFrozen, FrozenUntil: &after}, UserStatusFrozen},
+		{"期限が過ぎた凍結状態", User{Status: UserStatusFrozen, FrozenUntil: &before}, UserStatusNormal},
+		{"無期限の凍結状態", User{Status: UserStatusFrozen}, UserStatusFrozen},
+		{"期限前の利用停止状態", User{Status: UserStatusSuspended, SuspendedUntil: &after}, UserStatusSuspended},
+		{"期限が過ぎた利用停止状態", User{Status: UserStatusSuspended, SuspendedUntil: &before}, UserStatusNormal},
+		{"退会済み", User{Status: UserStatusClosed}, UserStatusClosed},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.user.StatusAt(clock); got != tc.want {
+				t.Errorf("StatusAt メソッドは %q を返すはずですが、%q を返しました。", tc.want, got)
+			}
+		})
+	}
+}
+
+// 本人確認待ちのユーザーを通常状態にするテスト。
+func TestActivate(t *testing.T) {
+	user := NewUser("", SystemClock)
+	user.Status = UserStatusPending
+
+	// 本人確認待ちのユーザーは凍結できない
+	if _, err := user.Freeze("OP1", "凍結理由", nil, SystemClock); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("本人確認待ちのユーザーに Freeze メソッドを実行しましたが、ErrInvalidStatusTransition ではなく %v が返りました。", err)
+	}
+
+	if _, err := user.Activate("OP1", "本人確認済み", SystemClock); err != nil {
+		t.Fatalf("本人確認待ちのユーザーに Activate メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if user.Status != UserStatusNormal {
+		t.Errorf("Activate メソッドを実行しましたが、ステータスが %q になっています。", user.Status)
+	}
+	if user.LastStatusChange == nil || user.LastStatusChange.OperatorID != "OP1" {
+		t.Errorf("Activate メソッドを実行しましたが、ステータス変更の記録が正しく残されていません: %+v", user.LastStatusChange)
+	}
+
+	// 本人確認待ち以外のユーザーは Activate で通常状態にできず、何も変更されない（凍結状態なら Unfreeze、利用停止状態なら LiftSuspension を使う）
+	frozen := user
+	if _, err := frozen.Freeze("OP2", "凍結理由", nil, SystemClock); err != nil {
+		t.Fatalf("通常状態のユーザーに Freeze メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	suspended := user
+	if _, err := suspended.Suspend(time.Date(3000, time.January, 1, 0, 0, 0, 0, time.UTC), "OP2", "利用停止理由", SystemClock); err != nil {
+		t.Fatalf("通常状態のユーザーに Suspend メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	for _, target := range []*User{&user, &frozen, &suspended} {
+		before := *target
+		changed, err := target.Activate("OP3", "本人確認済み", SystemClock)
+		var transitionErr *StatusTransitionError
+		if changed || !errors.As(err, &transitionErr) || transitionErr.From != before.Status || transitionErr.To != UserStatusNormal {
+			t.Errorf("ステータスが %q のユーザーに Activate メソッドを実行しましたが、changed=%t, err=%v が返りました。", before.Status, changed, err)
+		}
+		if target.Status != before.Status || target.LastStatusChange != before.LastStatusChange {
+			t.Errorf("ステータスが %q のユーザーに Activate メソッドを実行したところ、ユーザーが変更されました: %+v", before.Status, *target)
+		}
+	}
+}
+
+// ユーザーの利用停止・利用停止の解除のテスト。
+func TestSuspendLiftSuspension(t *testing.T) {
+	clock := NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	user := NewUser("", clock)
+	until := clock.Now().AddDate(0, 0, 7)
+
+	if _, err := user.Suspend(until, "OP1", "利用停止理由", clock); err != nil {
+		t.Fatalf("通常状態のユーザーに Suspend メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if !user.IsSuspended(clock) || user.SuspendedUntil == nil || !user.SuspendedUntil.Equal(until) {
+		t.Errorf("Suspend メソッドを実行しましたが、期限 %v の利用停止状態になっていません: %+v", until, user)
+	}
+
+	// 利用停止状態のユーザーを再度利用停止にすると、期限が変わる
+	extended := until.AddDate(1, 0, 0)
+	if _, err := user.Suspend(extended, "OP2", "延長理由", clock); err != nil {
+		t.Fatalf("利用停止状態のユーザーに Suspend メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if user.SuspendedUntil == nil || !user.SuspendedUntil.Equal(extended) || user.LastStatusChange.OperatorID != "OP2" {
+		t.Errorf("利用停止状態のユーザーに Suspend メソッドを実行しましたが、期限が %v に延長されていません: %+v", extended, user)
+	}
+
+	// 利用停止状態から凍結状態にすると、期限はなくなる
+	frozen := user
+	if _, err := frozen.Freeze("OP3", "凍結理由", nil, clock); err != nil {
+		t.Fatalf("利用停止状態のユーザーに Freeze メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if frozen.SuspendedUntil != nil {
+		t.Errorf("凍結状態にしましたが、利用停止の期限 %v が残っています。", *frozen.SuspendedUntil)
+	}
+
+	if _, err := user.LiftSuspension("OP4", "利用停止解除理由", clock); err != nil {
+		t.Fatalf("利用停止状態のユーザーに LiftSuspension メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if user.IsSuspended(clock) || user.SuspendedUntil != nil {
+		t.Errorf("LiftSuspension メソッドを実行しましたが、利用停止状態が解除されていません: %+v", user)
+	}
+
+	// 凍結状態のユーザーは利用停止にできない
+	if _, err := frozen.Suspend(until, "OP5", "利用停止理由", clock); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("凍結状態のユーザーに Suspend メソッドを実行しましたが、ErrInvalidStatusTransition ではなく %v が返りました。", err)
+	}
+}
+
+// 利用停止の期限のテスト。
+func TestSuspendUntil(t *testing.T) {
+	clock := NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	user := NewUser("", clock)
+
+	// 現在以前の日時は期限に指定できず、何も変更されない
+	for _, until := range []time.Time{clock.Now().Add(-time.Hour), clock.Now()} {
+		changed, err := user.Suspend(until, "OP1", "利用停止理由", clock)
+		if changed || !errors.Is(err, ErrSuspensionUntilNotFuture) {
+			t.Errorf("期限に %v を指定して Suspend メソッドを実行しましたが、changed=%t, err=%v が返りました。", until, changed, err)
+		}
+		if user.Status != UserStatusNormal || user.SuspendedUntil != nil || user.LastStatusChange != nil {
+			t.Errorf("期限に %v を指定して Suspend メソッドを実行したところ、ユーザーが変更されました: %+v", until, user)
+		}
+	}
+
+	until := clock.Now().AddDate(0, 0, 7)
+	if _, err := user.Suspend(until, "OP1", "7 日間の利用停止", clock); err != nil {
+		t.Fatalf("通常状態のユーザーに Suspend メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+
+	// 同じ期限で再度利用停止にしても、何も変更されない
+	if changed, err := user.Suspend(until, "OP2", "同じ期限", clock); err != nil || changed {
+		t.Fatalf("同じ期限で Suspend メソッドを実行しましたが、changed=%t, err=%v が返りました", changed, err)
+	}
+	if user.LastStatusChange.OperatorID != "OP1" {
+		t.Errorf("同じ期限で Suspend メソッドを実行しましたが、ステータス変更の記録が更新されました: %+v", *user.LastStatusChange)
+	}
+
+	// 期限の直前までは利用停止状態で、期限を過ぎると利用停止状態ではなくなる（ステータスは suspended のまま）
+	clock.Advance(7*24*time.Hour - time.Nanosecond)
+	if !user.IsSuspended(clock) {
+		t.Errorf("期限の直前ですが、IsSuspended メソッドが false を返しました。")
+	}
+	clock.Advance(time.Nanosecond)
+	if user.IsSuspended(clock) {
+		t.Errorf("期限を過ぎましたが、IsSuspended メソッドが true を返しました。")
+	}
+	if user.Status != UserStatusSuspended {
+		t.Errorf("期限を過ぎてもステータスは %q のままのはずですが、%q になっています。", UserStatusSuspended, user.Status)
+	}
+
+	// 期限が過ぎていても、ステータスが suspended であれば解除できる
+	if changed, err := user.LiftSuspension("OP3", "利用停止解除理由", clock); err != nil || !changed {
+		t.Fatalf("期限が過ぎたユーザーに LiftSuspension メソッドを実行しましたが、changed=%t, err=%v が返りました", changed, err)
+	}
+	if user.Status != UserStatusNormal || user.SuspendedUntil != nil {
+		t.Errorf("LiftSuspension メソッドを実行しましたが、利用停止状態が解除されていません: %+v", user)
+	}
+}
+
+// ユーザーの退会のテスト。
+func TestClose(t *testing.T) {
+	user := NewUser("", SystemClock)
+	if _, err := user.Close("OP1", "退会理由", SystemClock); err != nil {
+		t.Fatalf("通常状態のユーザーに Close メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if !user.IsClosed() {
+		t.Errorf("Close メソッドを実行しましたが、IsClosed メソッドが false を返しました。")
+	}
+
+	// 退会済みのユーザーを再度退会させても何もしない
+	if _, err := user.Close("OP2", "別の退会理由", SystemClock); err != nil {
+		t.Errorf("退会済みのユーザーに Close メソッドを実行しましたが、エラーが発生しました: %v", err)
+	}
+	if user.LastStatusChange.OperatorID != "OP1" {
+		t.Errorf("退会済みのユーザーに Close メソッドを実行しましたが、ステータス変更の記録が更新されました: %+v", *user.LastStatusChange)
+	}
+
+	// 退会済みのユーザーは他のステータスに変更できず、何も変更されない
+	changes := map[string]func() error{
+		"Activate": func() error { _, err := user.Activate("OP3", "理由", SystemClock); return err },
+		"Freeze":   func() error { _, err := user.Freeze("OP3", "理由", nil, SystemClock); return err },
+		"Suspend": func() error {
+			_, err := user.Suspend(time.Now().Add(time.Hour), "OP3", "理由", SystemClock)
+			return err
+		},
+	}
+	for name, change := range changes {
+		err := change()
+		var transitionErr *StatusTransitionError
+		if !errors.As(err, &transitionErr) || transitionErr.From != UserStatusClosed {
+			t.Errorf("退会済みのユーザーに %s メソッドを実行しましたが、*StatusTransitionError ではなく %v が返りました。", name, err)
+		}
+		if !user.IsClosed() || user.LastStatusChange.OperatorID != "OP1" || user.SuspendedUntil != nil {
+			t.Errorf("退会済みのユーザーに %s メソッドを実行したところ、ユーザーが変更されました: %+v", name, user)
+		}
+	}
+}
+
+// 削除済みのユーザーの復元のテスト。
+func TestRestore(t *testing.T) {
+	clock := NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	user := NewUser("", clock)
+	if user.IsDeleted() {
+		t.Errorf("新しいユーザーは削除済みでないはずですが、IsDeleted メソッドが true を返しました。")
+	}
+
+	clock.Advance(time.Hour)
+	user.Delete(clock)
+	if !user.IsDeleted() {
+		t.Errorf("Delete メソッドを実行しましたが、IsDeleted メソッドが false を返しました。")
+	}
+	if !user.DeletedAt.Equal(clock.Now()) || !user.UpdatedAt.Equal(clock.Now()) {
+		t.Errorf("Delete メソッドを実行しましたが、削除日時 %v と最終更新日時 %v が現在日時 %v になっていません。", *user.DeletedAt, user.UpdatedAt, clock.Now())
+	}
+	deletedAt := *user.DeletedAt
+
+	// 削除済みのユーザーを再度削除しても、削除日時は変わらない
+	clock.Advance(time.Hour)
+	user.Delete(clock)
+	if !user.DeletedAt.Equal(deletedAt) {
+		t.Errorf("削除済みのユーザーに Delete メソッドを実行しましたが、削除日時が %v に更新されました。", *user.DeletedAt)
+	}
+
+	user.Restore(clock)
+	if user.IsDeleted() {
+		t.Errorf("Restore メソッドを実行しましたが、IsDeleted メソッドが true を返しました。")
+	}
+	if !user.UpdatedAt.Equal(clock.Now()) {
+		t.Errorf("Restore メソッドを実行しましたが、最終更新日時 %v が現在日時 %v になっていません。", user.UpdatedAt, clock.Now())
+	}
+
+	// 削除済みでないユーザーを復元しても何も変わらない
+	updatedAt := user.UpdatedAt
+	clock.Advance(time.Hour)
+	user.Restore(clock)
+	if !user.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("削除済みでないユーザーに Restore メソッドを実行しましたが、最終更新日時が %v に更新されました。", user.UpdatedAt)
+	}
 }
 
 // ユーザーの名前変更のテスト。
 func TestChangeName(t *testing.T) {
 	t.Run("通常状態のユーザーの名前が変更できることのテスト。", func(t *testing.T) {
-		user := NewUser("oldname")
-		user.Unfreeze()
+		user := NewUser("oldname", SystemClock)
+		user.Unfreeze("OP1", "凍結解除理由", SystemClock)
+		registeredAt := user.RegisteredAt
 
-		err := user.ChangeName("newname")
+		_, err := user.ChangeName("newname", SystemClock)
 		if err != nil {
 			t.Fatalf(`ユーザーの名前を "oldname" から "newname" に変更しようとしましたが、エラーが発生しました: %v`, err)
 		}
@@ -50,19 +415,51 @@ func TestChangeName(t *testing.T) {
 		if user.Name != "newname" {
 			t.Errorf(`ユーザーの名前を "oldname" から "newname" に変更しようとしましたが、変更後の名前は %q となっています。`, user.Name)
 		}
+		if !user.UpdatedAt.After(registeredAt) {
+			t.Errorf("名前を変更しましたが、最終更新日時 %v が更新されていません。", user.UpdatedAt)
+		}
+	})
+
+	t.Run("名前が変わらない場合は何も変更しないことのテスト。", func(t *testing.T) {
+		user := NewUser("name", SystemClock)
+		updatedAt := user.UpdatedAt
+
+		changed, err := user.ChangeName("name", SystemClock)
+		if err != nil || changed {
+			t.Fatalf("同じ名前に変更しようとしましたが、changed=%t, err=%v が返りました", changed, err)
+		}
+		if !user.UpdatedAt.Equal(updatedAt) {
+			t.Errorf("同じ名前に変更しようとしましたが、最終更新日時が %v に更新されました。", user.UpdatedAt)
+		}
 	})
 
 	t.Run("凍結状態のユーザーの名前は変更できないことのテスト。", func(t *testing.T) {
-		user := NewUser("oldname")
-		user.Freeze()
+		user := NewUser("oldname", SystemClock)
+		user.Freeze("OP1", "凍結理由", nil, SystemClock)
 
-		err := user.ChangeName("newname")
+		_, err := user.ChangeName("newname", SystemClock)
 		if err == nil {
 			t.Fatalf("凍結状態のユーザーの名前は変更できないはずですが、ChangeName メソッドがエラーを返しませんでした。")
 		}
+		if !errors.Is(err, ErrUserFrozen) {
+			t.Errorf("ChangeName メソッドは ErrUserFrozen を返すはずですが、予期しないエラーが返りました: %v", err)
+		}
 
 		if user.Name != "oldname" {
 			t.Errorf("凍結状態のユーザーの名前は変更できないはずですが、%q に変更されています。", user.Name)
 		}
 	})
+
+	t.Run("退会済みのユーザーの名前は変更できないことのテスト。", func(t *testing.T) {
+		user := NewUser("oldname", SystemClock)
+		user.Close("OP1", "退会理由", SystemClock)
+
+		changed, err := user.ChangeName("newname", SystemClock)
+		if !errors.Is(err, ErrUserClosed) || changed {
+			t.Fatalf("ChangeName メソッドは ErrUserClosed を返すはずですが、changed=%t, err=%v が返りました", changed, err)
+		}
+		if user.Name != "oldname" {
+			t.Errorf("退会済みのユーザーの名前は変更できないはずですが、%q に変更されています。", user.Name)
+		}
+	})
 }
@@ -0,0 +1,124 @@
+// health パッケージは、オーケストレーター等から死活監視を行うためのエンドポイントを提供します。
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"nekonoshiri/go-echo-sample/logging"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// 依存先１つあたりの疎通確認のタイムアウト。
+	checkTimeout = 3 * time.Second
+)
+
+// 依存先（データベース等）の疎通確認。
+type Checker interface {
+	// 依存先の名前（例: "mongo"）。レスポンスに含まれます。
+	Name() string
+	// 依存先と疎通できれば nil を、できなければエラーを返します。
+	Check(ctx context.Context) error
+}
+
+// 疎通確認の状態。
+const (
+	StatusOK          = "ok"          // 正常。
+	StatusUnavailable = "unavailable" // 異常。
+)
+
+// 死活監視のエンドポイントのレスポンス。
+type Response struct {
+	// 全体の状態。ok か unavailable です。
+	Status string `json:"status"`
+	// 依存先毎の疎通確認の結果。Liveness では省略されます。
+	Checks []CheckResult `json:"checks,omitempty"`
+}
+
+// 依存先の疎通確認の結果。
+type CheckResult struct {
+	// 依存先の名前。
+	Name string `json:"name"`
+	// 状態。ok か unavailable です。
+	Status string `json:"status"`
+	// 疎通確認にかかった時間（ミリ秒）。
+	LatencyMilliseconds float64 `json:"latencyMs"`
+}
+
+// 死活監視のエンドポイントのハンドラー。
+type Handler struct {
+	checkers     []Checker
+	shuttingDown atomic.Bool
+}
+
+// 死活監視のエンドポイントのハンドラーを返します。
+// Readiness では、checkers のすべての依存先と疎通できる場合のみ準備完了とみなします。
+func NewHandler(checkers ...Checker) *Handler {
+	return &Handler{
+		checkers: checkers,
+	}
+}
+
+// シャットダウン中であることを設定します。これ以降、Readiness は常に準備未完了を返します。
+func (h *Handler) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// プロセスが生きていることを確認するエンドポイント（/healthz）です。
+// 依存先の疎通確認は行わず、常に HTTP ステータスコード 200 を返します。
+func (h *Handler) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, Response{Status: StatusOK})
+}
+
+// リクエストを受け付ける準備ができていることを確認するエンドポイント（/readyz）です。
+// すべての依存先と疎通でき、かつシャットダウン中でない場合は HTTP ステータスコード 200 を、
+// そうでなければ 503 を返します。
+func (h *Handler) Readiness(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	response := Response{
+		Status: StatusOK,
+		Checks: make([]CheckResult, 0, len(h.checkers)),
+	}
+	for _, checker := range h.checkers {
+		result := check(ctx, checker)
+		if result.Status != StatusOK {
+			response.Status = StatusUnavailable
+		}
+		response.Checks = append(response.Checks, result)
+	}
+	if h.shuttingDown.Load() {
+		response.Status = StatusUnavailable
+	}
+
+	if response.Status != StatusOK {
+		return c.JSON(http.StatusServiceUnavailable, response)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// 依存先の疎通確認を行います。
+// Note: エラーの詳細は内部情報を含みうるため、レスポンスには含めずログにのみ出力します。
+func check(ctx context.Context, checker Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	latency := time.Since(start)
+
+	result := CheckResult{
+		Name:                checker.Name(),
+		Status:              StatusOK,
+		LatencyMilliseconds: float64(latency.Microseconds()) / 1000,
+	}
+	if err != nil {
+		logging.FromContext(ctx).Warn("依存先との疎通確認に失敗しました", "dependency", checker.Name(), "error", err)
+		result.Status = StatusUnavailable
+	}
+	return result
+}
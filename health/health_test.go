@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// テスト用の Checker。
+type mockChecker struct {
+	name string
+	err  error
+}
+
+func (checker *mockChecker) Name() string {
+	return checker.name
+}
+
+func (checker *mockChecker) Check(ctx context.Context) error {
+	return checker.err
+}
+
+// テスト用に、ハンドラーを実行してステータスコードとレスポンスを返します。
+func serve(t *testing.T, handler echo.HandlerFunc) (int, Response) {
+	t.Helper()
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+
+	if err := handler(c); err != nil {
+		t.Fatalf("ハンドラーがエラーを返しました: %v", err)
+	}
+
+	var response Response
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("レスポンスボディのデコードに失敗しました: %v", err)
+	}
+	return recorder.Code, response
+}
+
+// Liveness は依存先と疎通できなくても 200 を返すことのテスト。
+func TestLiveness(t *testing.T) {
+	h := NewHandler(&mockChecker{name: "mongo", err: errors.New("接続できません")})
+
+	statusCode, response := serve(t, h.Liveness)
+	if statusCode != http.StatusOK {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusOK, statusCode)
+	}
+	if response.Status != StatusOK {
+		t.Errorf("期待される状態は %q ですが、%q が返りました", StatusOK, response.Status)
+	}
+}
+
+// Readiness のテスト。
+func TestReadiness(t *testing.T) {
+	testCases := []struct {
+		name           string    // テストケース名
+		checkers       []Checker // 依存先
+		shuttingDown   bool      // シャットダウン中かどうか
+		wantStatusCode int       // 期待される HTTP ステータスコード
+		wantStatus     string    // 期待される全体の状態
+		wantChecks     []string  // 期待される依存先毎の状態
+	}{
+		{
+			name:           "依存先がない場合",
+			wantStatusCode: http.StatusOK,
+			wantStatus:     StatusOK,
+			wantChecks:     []string{},
+		},
+		{
+			name:           "すべての依存先と疎通できる場合",
+			checkers:       []Checker{&mockChecker{name: "a"}, &mockChecker{name: "b"}},
+			wantStatusCode: http.StatusOK,
+			wantStatus:     StatusOK,
+			wantChecks:     []string{StatusOK, StatusOK},
+		},
+		{
+			name:           "疎通できない依存先がある場合",
+			checkers:       []Checker{&mockChecker{name: "a"}, &mockChecker{name: "b", err: errors.New("接続できません")}},
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantStatus:     StatusUnavailable,
+			wantChecks:     []string{StatusOK, StatusUnavailable},
+		},
+		{
+			name:           "シャットダウン中の場合",
+			checkers:       []Checker{&mockChecker{name: "a"}},
+			shuttingDown:   true,
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantStatus:     StatusUnavailable,
+			wantChecks:     []string{StatusOK},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(tc.checkers...)
+			if tc.shuttingDown {
+				h.SetShuttingDown()
+			}
+
+			statusCode, response := serve(t, h.Readiness)
+			if statusCode != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, statusCode)
+			}
+			if response.Status != tc.wantStatus {
+				t.Errorf("期待される状態は %q ですが、%q が返りました", tc.wantStatus, response.Status)
+			}
+			if len(response.Checks) != len(tc.wantChecks) {
+				t.Fatalf("%d 件の依存先の結果が返るはずですが、%d 件返りました: %+v", len(tc.wantChecks), len(response.Checks), response.Checks)
+			}
+			for i, check := range response.Checks {
+				if check.Name != tc.checkers[i].Name() || check.Status != tc.wantChecks[i] {
+					t.Errorf("依存先 %q の状態は %q のはずですが、%+v が返りました", tc.checkers[i].Name(), tc.wantChecks[i], check)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// i18n パッケージは、メッセージの多言語化を提供します。
+//
+// メッセージは安定したメッセージ ID をキーとするカタログ（[Catalog]）に言語毎に定義し、
+// リクエストの Accept-Language ヘッダーから選んだ言語（[ParseAcceptLanguage]）で取り出します。
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 言語。BCP 47 の言語タグの主言語サブタグです。
+type Language string
+
+// 対応している言語。
+const (
+	Japanese Language = "ja" // 日本語。
+	English  Language = "en" // 英語。
+)
+
+// HTTP ヘッダー名。
+const (
+	HeaderAcceptLanguage  = "Accept-Language"
+	HeaderContentLanguage = "Content-Language"
+)
+
+// Accept-Language ヘッダーで対応している言語が指定されなかった場合の言語。
+const DefaultLanguage = Japanese
+
+// 対応している言語の一覧。
+var SupportedLanguages = []Language{Japanese, English}
+
+// メッセージカタログ。メッセージ ID から、言語毎のメッセージへの対応表です。
+// メッセージは fmt.Sprintf の書式で、引数を埋め込むことができます。
+type Catalog map[string]map[Language]string
+
+// メッセージ ID に対応する、指定した言語のメッセージを返します。args がある場合は、メッセージに埋め込みます。
+// 指定した言語のメッセージがない場合は DefaultLanguage のメッセージを、
+// メッセージ ID がカタログにない場合はメッセージ ID をそのまま返します。
+func (catalog Catalog) Message(language Language, id string, args ...interface{}) string {
+	translations, ok := catalog[id]
+	if !ok {
+		return id
+	}
+	message, ok := translations[language]
+	if !ok {
+		message = translations[DefaultLanguage]
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// Accept-Language ヘッダーの値から、対応している言語のうち最も優先されるものを返します。
+// 対応している言語が含まれない場合（ヘッダーがない場合を含む）は DefaultLanguage を返します。
+//
+// 言語タグは主言語サブタグのみで比較します（例: en-US は en とみなします）。
+// 品質値（q）が同じ場合は、先に指定されたものを優先します。
+func ParseAcceptLanguage(header string) Language {
+	best := DefaultLanguage
+	bestQuality := 0.0
+	for _, languageRange := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(languageRange, ";")
+		primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+		language := Language(strings.ToLower(primary))
+		if language == "*" {
+			language = DefaultLanguage
+		}
+		if !isSupported(language) {
+			continue
+		}
+
+		quality := parseQuality(params)
+		if quality > bestQuality {
+			best = language
+			bestQuality = quality
+		}
+	}
+	return best
+}
+
+// Accept-Language ヘッダーの言語範囲のパラメーター（例: "q=0.8"）から品質値を返します。
+// 品質値が指定されていない場合は 1 を、不正な場合は 0 を返します。
+func parseQuality(params string) float64 {
+	name, value, ok := strings.Cut(strings.TrimSpace(params), "=")
+	if !ok || strings.TrimSpace(name) != "q" {
+		return 1
+	}
+	quality, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || quality < 0 || quality > 1 {
+		return 0
+	}
+	return quality
+}
+
+func isSupported(language Language) bool {
+	for _, supported := range SupportedLanguages {
+		if language == supported {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"testing"
+)
+
+// Accept-Language ヘッダーから言語を選ぶことのテスト。
+func TestParseAcceptLanguage(t *testing.T) {
+	testCases := []struct {
+		header       string   // Accept-Language ヘッダー
+		wantLanguage Language // 期待される言語
+	}{
+		{header: "", wantLanguage: Japanese},
+		{header: "en", wantLanguage: English},
+		{header: "en-US,en;q=0.9", wantLanguage: English},
+		{header: "EN-gb", wantLanguage: English},
+		{header: "ja,en;q=0.5", wantLanguage: Japanese},
+		{header: "ja;q=0.5, en;q=0.8", wantLanguage: English},
+		{header: "fr, en;q=0.1", wantLanguage: English},
+		{header: "fr, de", wantLanguage: Japanese},
+		{header: "en;q=0", wantLanguage: Japanese},
+		{header: "en;q=abc", wantLanguage: Japanese},
+		{header: "en, ja", wantLanguage: English},
+		{header: "*", wantLanguage: Japanese},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			if language := ParseAcceptLanguage(tc.header); language != tc.wantLanguage {
+				t.Errorf("期待される言語は %q ですが、%q が返りました", tc.wantLanguage, language)
+			}
+		})
+	}
+}
+
+// メッセージカタログからメッセージを取り出すことのテスト。
+func TestCatalogMessage(t *testing.T) {
+	catalog := Catalog{
+		"greeting": {Japanese: "こんにちは、%s さん", English: "Hello, %s"},
+		"only_ja":  {Japanese: "日本語のみ"},
+	}
+
+	testCases := []struct {
+		name        string        // テストケース名
+		language    Language      // 言語
+		id          string        // メッセージ ID
+		args        []interface{} // メッセージに埋め込む引数
+		wantMessage string        // 期待されるメッセージ
+	}{
+		{name: "日本語", language: Japanese, id: "greeting", args: []interface{}{"太郎"}, wantMessage: "こんにちは、太郎 さん"},
+		{name: "英語", language: English, id: "greeting", args: []interface{}{"Taro"}, wantMessage: "Hello, Taro"},
+		{name: "翻訳がない", language: English, id: "only_ja", wantMessage: "日本語のみ"},
+		{name: "カタログにない", language: English, id: "unknown", wantMessage: "unknown"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if message := catalog.Message(tc.language, tc.id, tc.args...); message != tc.wantMessage {
+				t.Errorf("期待されるメッセージは %q ですが、%q が返りました", tc.wantMessage, message)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package infra
+
+import (
+	"context"
+
+	"nekonoshiri/go-echo-sample/health"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type mongoHealthChecker struct {
+	client *mongo.Client
+}
+
+// *mongoHealthChecker が health.Checker を実装していることの確認
+var _ health.Checker = (*mongoHealthChecker)(nil)
+
+// MongoDB（プライマリ）との疎通確認を行う health.Checker の実装を返します。
+func NewMongoHealthChecker(client *mongo.Client) *mongoHealthChecker {
+	return &mongoHealthChecker{
+		client: client,
+	}
+}
+
+func (checker *mongoHealthChecker) Name() string {
+	return "mongo"
+}
+
+func (checker *mongoHealthChecker) Check(ctx context.Context) error {
+	return checker.client.Ping(ctx, readpref.Primary())
+}
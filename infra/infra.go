@@ -1,7 +1,2 @@
 // infra パッケージはリポジトリやサービスの実装を提供します。
 package infra
-
-const (
-	// MongoDB のデータベース名
-	mongoDatabase = "user_management"
-)
@@ -0,0 +1,188 @@
+package infra
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+)
+
+type memoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]domain.User
+}
+
+// *memoryUserRepository が domain.UserRepository を実装していることの確認
+var _ domain.UserRepository = (*memoryUserRepository)(nil)
+
+// メモリ上にユーザーを保持する UserRepository の実装を返します。
+// 複数のゴルーチンから同時に使用できます。
+// データはプロセスの終了とともに失われるため、テストやローカルでの開発用です。
+func NewMemoryUserRepository() *memoryUserRepository {
+	return &memoryUserRepository{
+		users: map[string]domain.User{},
+	}
+}
+
+func (repo *memoryUserRepository) Get(ctx context.Context, userID string) (*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	user, ok := repo.users[userID]
+	if !ok || user.IsDeleted() {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return cloneUser(&user), nil
+}
+
+func (repo *memoryUserRepository) GetIncludingDeleted(ctx context.Context, userID string) (*domain.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	user, ok := repo.users[userID]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return cloneUser(&user), nil
+}
+
+func (repo *memoryUserRepository) List(ctx context.Context, exclusiveStartKey string, limit int) ([]domain.User, string, error) {
+	return repo.list(ctx, exclusiveStartKey, limit, func(user *domain.User) bool {
+		return true
+	})
+}
+
+func (repo *memoryUserRepository) ListExpiredFrozen(ctx context.Context, now time.Time, exclusiveStartKey string, limit int) ([]domain.User, string, error) {
+	return repo.list(ctx, exclusiveStartKey, limit, func(user *domain.User) bool {
+		return user.Status == domain.UserStatusFrozen && user.FrozenUntil != nil && !user.FrozenUntil.After(now)
+	})
+}
+
+// 削除済みでなく、match が true を返すユーザーの一覧を、List と同じ方法で取得します。
+func (repo *memoryUserRepository) list(ctx context.Context, exclusiveStartKey string, limit int, match func(user *domain.User) bool) ([]domain.User, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	// mongoUserRepository と同じく、ユーザー ID の昇順に返す
+	userIDs := make([]string, 0, len(repo.users))
+	for userID, user := range repo.users {
+		if userID > exclusiveStartKey && !user.IsDeleted() && match(&user) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	sort.Strings(userIDs)
+
+	users := []domain.User{}
+	var lastEvaluatedKey string = ""
+
+	for _, userID := range userIDs {
+		// limit が 0 または負数の場合は制限なし
+		if limit > 0 && len(users) >= limit {
+			break
+		}
+		user := repo.users[userID]
+		users = append(users, *cloneUser(&user))
+		lastEvaluatedKey = userID
+	}
+
+	// 続きが取得できない場合 lastEvaluatedKey は空文字列になる
+	if len(users) == len(userIDs) {
+		lastEvaluatedKey = ""
+	}
+
+	return users, lastEvaluatedKey, nil
+}
+
+func (repo *memoryUserRepository) Put(ctx context.Context, user *domain.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	// 保存されていない場合のバージョンはゼロ値の 0
+	if stored := repo.users[user.UserID]; stored.Version != user.Version {
+		return domain.ErrConflict
+	}
+	user.Version++
+	repo.users[user.UserID] = *cloneUser(user)
+	return nil
+}
+
+func (repo *memoryUserRepository) Delete(ctx context.Context, userID string, deletedAt time.Time) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	user, ok := repo.users[userID]
+	if !ok || user.IsDeleted() {
+		return false, nil
+	}
+	deletedAt = deletedAt.UTC()
+	user.DeletedAt = &deletedAt
+	user.UpdatedAt = deletedAt
+	user.Version++
+	repo.users[userID] = user
+	return true, nil
+}
+
+func (repo *memoryUserRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	purged := 0
+	for userID, user := range repo.users {
+		if user.IsDeleted() && user.DeletedAt.Before(deletedBefore) {
+			delete(repo.users, userID)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// ユーザーのディープコピーを返します。
+// 呼び出し元が返されたユーザーを変更しても、リポジトリ内のユーザーに影響しないようにするために使用します。
+func cloneUser(user *domain.User) *domain.User {
+	clone := *user
+	if user.LastStatusChange != nil {
+		change := *user.LastStatusChange
+		clone.LastStatusChange = &change
+	}
+	if user.DeletedAt != nil {
+		deletedAt := *user.DeletedAt
+		clone.DeletedAt = &deletedAt
+	}
+	if user.SuspendedUntil != nil {
+		suspendedUntil := *user.SuspendedUntil
+		clone.SuspendedUntil = &suspendedUntil
+	}
+	if user.FrozenUntil != nil {
+		frozenUntil := *user.FrozenUntil
+		clone.FrozenUntil = &frozenUntil
+	}
+	return &clone
+}
@@ -0,0 +1,76 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/domain/repositorytest"
+)
+
+// メモリ上の UserRepository の実装が、domain.UserRepository の仕様を満たしていることのテスト。
+func TestMemoryUserRepository(t *testing.T) {
+	repositorytest.TestUserRepository(t, func(t *testing.T) domain.UserRepository {
+		return NewMemoryUserRepository()
+	})
+}
+
+// メモリ上のリポジトリで、保存に使ったユーザーを変更しても保存されているユーザーは変わらないことのテスト。
+func TestMemoryPutCopiesUser(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryUserRepository()
+
+	user := domain.User{
+		UserID:       "U1",
+		Name:         "ユーザー1",
+		Status:       domain.UserStatusFrozen,
+		RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		LastStatusChange: &domain.UserStatusChange{
+			OperatorID: "OP1",
+			Reason:     "凍結理由",
+			ChangedAt:  time.Date(1000, time.January, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	if err := repo.Put(ctx, &user); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+
+	user.Name = "変更後"
+	user.LastStatusChange.Reason = "変更後"
+
+	gotUser, err := repo.Get(ctx, user.UserID)
+	if err != nil {
+		t.Fatalf("保存したユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	if gotUser.Name != "ユーザー1" || gotUser.LastStatusChange.Reason != "凍結理由" {
+		t.Fatalf("保存に使ったユーザーを変更したところ、保存されているユーザーも変更されました: %+v", *gotUser)
+	}
+}
+
+// メモリ上のリポジトリを複数のゴルーチンから同時に使用できることのテスト（-race を付けて実行してください）。
+func TestMemoryConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryUserRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			user := domain.NewUser(fmt.Sprintf("ユーザー%d", i), domain.SystemClock)
+			if err := repo.Put(ctx, &user); err != nil {
+				t.Errorf("ユーザーの保存に失敗しました: %v", err)
+			}
+			if _, _, err := repo.List(ctx, "", -1); err != nil {
+				t.Errorf("ユーザーの一覧を取得しようとしましたが、エラーが発生しました: %v", err)
+			}
+			if _, err := repo.Delete(ctx, user.UserID, time.Now()); err != nil {
+				t.Errorf("ユーザーの削除に失敗しました: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
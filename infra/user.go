@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/logging"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -24,6 +25,77 @@ type userDocument struct {
 	Name         string            `bson:"name"`
 	Status       domain.UserStatus `bson:"status"`
 	RegisteredAt time.Time         `bson:"registered_at"`
+	// Note: $set で上書きした際に記録が消えるように、omitempty は付けません（nil は null として保存されます）。
+	LastStatusChange *userStatusChangeDocument `bson:"last_status_change"`
+	// バージョン。バージョンを導入する前に保存されたドキュメントにはないため、その場合は 0 として扱います。
+	Version int64 `bson:"version"`
+	// 最終更新日時。導入する前に保存されたドキュメントにはない（nil になる）ため、その場合は toUser で補います。
+	UpdatedAt *time.Time `bson:"updated_at"`
+	// 削除日時。削除されていない場合は null です（論理削除を導入する前に保存されたドキュメントにはありませんが、フィルターの null はフィールドがない場合にも一致します）。
+	DeletedAt *time.Time `bson:"deleted_at"`
+	// 利用停止の期限。利用停止状態でない場合は null です。
+	SuspendedUntil *time.Time `bson:"suspended_until"`
+	FrozenUntil    *time.Time `bson:"frozen_until"`
+}
+
+type userStatusChangeDocument struct {
+	OperatorID string    `bson:"operator_id"`
+	Reason     string    `bson:"reason"`
+	ChangedAt  time.Time `bson:"changed_at"`
+}
+
+// domain.User から userDocument を作成します。
+func newUserDocument(user *domain.User) *userDocument {
+	document := &userDocument{
+		UserID:         user.UserID,
+		Name:           user.Name,
+		Status:         user.Status,
+		RegisteredAt:   user.RegisteredAt,
+		Version:        user.Version,
+		UpdatedAt:      &user.UpdatedAt,
+		DeletedAt:      user.DeletedAt,
+		SuspendedUntil: user.SuspendedUntil,
+		FrozenUntil:    user.FrozenUntil,
+	}
+	if change := user.LastStatusChange; change != nil {
+		document.LastStatusChange = &userStatusChangeDocument{
+			OperatorID: change.OperatorID,
+			Reason:     change.Reason,
+			ChangedAt:  change.ChangedAt,
+		}
+	}
+	return document
+}
+
+// userDocument を domain.User に変換します。
+func (document *userDocument) toUser() *domain.User {
+	user := &domain.User{
+		UserID:         document.UserID,
+		Name:           document.Name,
+		Status:         document.Status,
+		RegisteredAt:   document.RegisteredAt,
+		Version:        document.Version,
+		DeletedAt:      document.DeletedAt,
+		SuspendedUntil: document.SuspendedUntil,
+		FrozenUntil:    document.FrozenUntil,
+	}
+	if change := document.LastStatusChange; change != nil {
+		user.LastStatusChange = &domain.UserStatusChange{
+			OperatorID: change.OperatorID,
+			Reason:     change.Reason,
+			ChangedAt:  change.ChangedAt,
+		}
+	}
+	if document.UpdatedAt != nil {
+		user.UpdatedAt = *document.UpdatedAt
+	} else {
+		// 最終更新日時がない場合は、分かる範囲で最後に変更された日時とする（名前の変更日時は記録されていない）
+		user.UpdatedAt = user.RegisteredAt
+		if change := user.LastStatusChange; change != nil && change.ChangedAt.After(user.UpdatedAt) {
+			user.UpdatedAt = change.ChangedAt
+		}
+	}
+	return user
 }
 
 type mongoUserRepository struct {
@@ -34,10 +106,11 @@ type mongoUserRepository struct {
 var _ domain.UserRepository = (*mongoUserRepository)(nil)
 
 // MongoDB を用いた UserRepository の実装を返します。
-// 第２引数で、デフォルトで使用するデータベースやコレクションを変更できます（テスト時に有用です）。
+// ユーザーは第１引数のデータベースの users コレクションに保存されます。
+// 第２引数で、デフォルトで使用するコレクションを変更できます（テスト時に有用です）。
 // 第３引数以降は無視されます。
-func NewMongoUserRepository(client *mongo.Client, collection ...*mongo.Collection) *mongoUserRepository {
-	col := client.Database(mongoDatabase).Collection(userCollection)
+func NewMongoUserRepository(database *mongo.Database, collection ...*mongo.Collection) *mongoUserRepository {
+	col := database.Collection(userCollection)
 	if len(collection) > 0 {
 		col = collection[0]
 	}
@@ -47,28 +120,63 @@ func NewMongoUserRepository(client *mongo.Client, collection ...*mongo.Collectio
 }
 
 func (repo *mongoUserRepository) Get(ctx context.Context, userID string) (*domain.User, error) {
+	return repo.get(ctx, userID, false)
+}
+
+func (repo *mongoUserRepository) GetIncludingDeleted(ctx context.Context, userID string) (*domain.User, error) {
+	return repo.get(ctx, userID, true)
+}
+
+// ユーザーを取得します。includeDeleted が false の場合、削除済みのユーザーは見つからないものとして扱います。
+func (repo *mongoUserRepository) get(ctx context.Context, userID string, includeDeleted bool) (*domain.User, error) {
 	filter := bson.M{"_id": userID}
+	if !includeDeleted {
+		filter["deleted_at"] = nil
+	}
 
 	var result *userDocument
 	if err := repo.collection.FindOne(ctx, filter).Decode(&result); err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, domain.ErrUserNotFound
 		}
+		logging.FromContext(ctx).Error("MongoDB からのユーザーの取得に失敗しました", "user_id", userID, "error", err)
 		return nil, fmt.Errorf("ユーザーの取得に失敗しました: %w", err)
 	}
 
-	return &domain.User{
-		UserID:       result.UserID,
-		Name:         result.Name,
-		Status:       result.Status,
-		RegisteredAt: result.RegisteredAt,
-	}, nil
+	return result.toUser(), nil
+}
+
+// ユーザーのコレクションのインデックス。
+var userIndexes = []mongo.IndexModel{
+	// ListExpiredFrozen で、凍結の期限が過ぎたユーザーを探すため
+	{Keys: bson.D{{Key: "frozen_until", Value: 1}}, Options: options.Index().SetName("frozen_until")},
+}
+
+// ユーザーのコレクションに、必要なインデックスを作成します。既に作成されているインデックスはそのままです。
+// サーバーの起動時に呼び出してください。
+func (repo *mongoUserRepository) EnsureIndexes(ctx context.Context) error {
+	if _, err := repo.collection.Indexes().CreateMany(ctx, userIndexes); err != nil {
+		logging.FromContext(ctx).Error("MongoDB のインデックスの作成に失敗しました", "collection", repo.collection.Name(), "error", err)
+		return fmt.Errorf("インデックスの作成に失敗しました: %w", err)
+	}
+	return nil
 }
 
 func (repo *mongoUserRepository) List(ctx context.Context, exclusiveStartKey string, limit int) ([]domain.User, string, error) {
-	filter := bson.M{}
+	return repo.list(ctx, bson.M{}, exclusiveStartKey, limit)
+}
+
+func (repo *mongoUserRepository) ListExpiredFrozen(ctx context.Context, now time.Time, exclusiveStartKey string, limit int) ([]domain.User, string, error) {
+	// Note: $lte は日時同士でのみ比較するため、期限が null（無期限）のドキュメントは一致しません。
+	filter := bson.M{"status": domain.UserStatusFrozen, "frozen_until": bson.M{"$lte": now}}
+	return repo.list(ctx, filter, exclusiveStartKey, limit)
+}
+
+// 削除済みでなく、filter に一致するユーザーの一覧を、List と同じ方法で取得します。filter は変更されます。
+func (repo *mongoUserRepository) list(ctx context.Context, filter bson.M, exclusiveStartKey string, limit int) ([]domain.User, string, error) {
+	filter["deleted_at"] = nil
 	if exclusiveStartKey != "" {
-		filter = bson.M{"_id": bson.M{"$gt": exclusiveStartKey}}
+		filter["_id"] = bson.M{"$gt": exclusiveStartKey}
 	}
 
 	opts := options.Find().SetSort(bson.M{"_id": 1})
@@ -80,6 +188,7 @@ func (repo *mongoUserRepository) List(ctx context.Context, exclusiveStartKey str
 
 	cursor, err := repo.collection.Find(ctx, filter, opts)
 	if err != nil {
+		logging.FromContext(ctx).Error("MongoDB からのユーザー一覧の取得に失敗しました", "exclusive_start_key", exclusiveStartKey, "error", err)
 		return nil, "", fmt.Errorf("ユーザーの取得に失敗しました: %w", err)
 	}
 	defer cursor.Close(ctx)
@@ -95,18 +204,15 @@ func (repo *mongoUserRepository) List(ctx context.Context, exclusiveStartKey str
 
 		var result *userDocument
 		if err := cursor.Decode(&result); err != nil {
+			logging.FromContext(ctx).Error("MongoDB から取得したユーザーのデコードに失敗しました", "error", err)
 			return nil, "", fmt.Errorf("取得したユーザーデータのデコードに失敗しました: %w", err)
 		}
-		users = append(users, domain.User{
-			UserID:       result.UserID,
-			Name:         result.Name,
-			Status:       result.Status,
-			RegisteredAt: result.RegisteredAt,
-		})
+		users = append(users, *result.toUser())
 		lastEvaluatedKey = result.UserID
 	}
 
 	if err := cursor.Err(); err != nil {
+		logging.FromContext(ctx).Error("MongoDB からのユーザー一覧の取得中にエラーが発生しました", "error", err)
 		return nil, "", fmt.Errorf("ユーザーの取得中にエラーが発生しました: %w", err)
 	}
 
@@ -119,29 +225,60 @@ func (repo *mongoUserRepository) List(ctx context.Context, exclusiveStartKey str
 }
 
 func (repo *mongoUserRepository) Put(ctx context.Context, user *domain.User) error {
-	filter := bson.M{"_id": user.UserID}
-	update := bson.M{"$set": userDocument{
-		UserID:       user.UserID,
-		Name:         user.Name,
-		Status:       user.Status,
-		RegisteredAt: user.RegisteredAt,
-	}}
-
-	_, err := repo.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	// 保存されているバージョンが一致する場合のみ更新する。
+	// バージョンが 0 の場合は、ドキュメントがなければ挿入し（upsert）、バージョンのないドキュメントがあれば更新する。
+	// バージョンのあるドキュメントがある場合はフィルターに一致せず、挿入しようとして _id が重複する。
+	filter := bson.M{"_id": user.UserID, "version": user.Version}
+	if user.Version == 0 {
+		filter["version"] = bson.M{"$exists": false}
+	}
+	document := newUserDocument(user)
+	document.Version++
+	update := bson.M{"$set": document}
+
+	result, err := repo.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(user.Version == 0))
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrConflict
+		}
+		logging.FromContext(ctx).Error("MongoDB へのユーザーの保存に失敗しました", "user_id", user.UserID, "error", err)
 		return fmt.Errorf("ユーザーの保存に失敗しました: %w", err)
 	}
+	if result.MatchedCount == 0 && result.UpsertedCount == 0 {
+		return domain.ErrConflict
+	}
 
+	user.Version = document.Version
 	return nil
 }
 
-func (repo *mongoUserRepository) Delete(ctx context.Context, userID string) error {
-	filter := bson.M{"_id": userID}
+func (repo *mongoUserRepository) Delete(ctx context.Context, userID string, deletedAt time.Time) (bool, error) {
+	// 削除済みのユーザーはフィルターに一致しない（削除日時を更新しない）
+	filter := bson.M{"_id": userID, "deleted_at": nil}
+	update := bson.M{
+		"$set": bson.M{"deleted_at": deletedAt.UTC(), "updated_at": deletedAt.UTC()},
+		// バージョンのないドキュメントは 0 として扱うので、1 になる
+		"$inc": bson.M{"version": 1},
+	}
 
-	_, err := repo.collection.DeleteOne(ctx, filter)
+	result, err := repo.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return fmt.Errorf("ユーザーの削除に失敗しました: %w", err)
+		logging.FromContext(ctx).Error("MongoDB のユーザーの削除に失敗しました", "user_id", userID, "error", err)
+		return false, fmt.Errorf("ユーザーの削除に失敗しました: %w", err)
 	}
 
-	return nil
+	return result.MatchedCount > 0, nil
+}
+
+func (repo *mongoUserRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	// Note: $lt は日時同士でのみ比較するため、削除日時が null のドキュメントは一致しません。
+	filter := bson.M{"deleted_at": bson.M{"$lt": deletedBefore}}
+
+	result, err := repo.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		logging.FromContext(ctx).Error("MongoDB からの削除済みユーザーの完全削除に失敗しました", "deleted_before", deletedBefore, "error", err)
+		return 0, fmt.Errorf("削除済みユーザーの完全削除に失敗しました: %w", err)
+	}
+
+	return int(result.DeletedCount), nil
 }
@@ -0,0 +1,37 @@
+// job パッケージは、サーバーと並行してバックグラウンドで定期的に実行するジョブを提供します。
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"nekonoshiri/go-echo-sample/logging"
+)
+
+// ジョブ。ctx がキャンセルされた場合は、できるだけ早く戻ってください。
+type Func func(ctx context.Context) error
+
+// ctx がキャンセルされるまで、interval 毎に job を実行します。最初の実行は interval 後ではなく、すぐに行います。
+// job がエラーを返した場合は、ログに出力して実行を続けます。
+// job には logger を持つ（[logging.FromContext] で取得できる）コンテキストを渡します。
+// ctx がキャンセルされ、実行中の job が戻るまで戻りません。
+func RunPeriodically(ctx context.Context, logger *slog.Logger, name string, interval time.Duration, job Func) {
+	logger = logger.With("job", name)
+	ctx = logging.WithLogger(ctx, logger)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := job(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("ジョブの実行に失敗しました", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/logging"
+)
+
+// RunPeriodically がすぐに１回目を実行し、その後も定期的に実行し続け、ctx のキャンセルで戻ることのテスト。
+func TestRunPeriodically(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, "info")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var count int32
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunPeriodically(ctx, logger, "test", 10*time.Millisecond, func(ctx context.Context) error {
+			// エラーを返しても実行を続ける
+			if atomic.AddInt32(&count, 1) >= 3 {
+				cancel()
+			}
+			return errors.New("ジョブのエラー")
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(1 * time.Second):
+		t.Fatalf("ctx をキャンセルしましたが、RunPeriodically が戻りませんでした")
+	}
+
+	if got := atomic.LoadInt32(&count); got != 3 {
+		t.Errorf("ジョブは 3 回実行されるはずですが、%d 回実行されました", got)
+	}
+	// キャンセルされた後のエラーは出力しないので、2 回分だけ出力される
+	if got := strings.Count(buf.String(), "ジョブの実行に失敗しました"); got != 2 {
+		t.Errorf("ジョブのエラーは 2 回出力されるはずですが、%d 回出力されました:\n%s", got, buf.String())
+	}
+	if !strings.Contains(buf.String(), `"job":"test"`) {
+		t.Errorf("ログにジョブの名前が含まれていません:\n%s", buf.String())
+	}
+}
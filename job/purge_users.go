@@ -0,0 +1,28 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/logging"
+)
+
+// 削除してから retention 以上経過した削除済みのユーザーを、完全に削除するジョブを返します。
+// 経過した時間は clock の現在日時で判定します。
+func PurgeDeletedUsers(userRepository domain.UserRepository, retention time.Duration, clock domain.Clock) Func {
+	return func(ctx context.Context) error {
+		deletedBefore := clock.Now().Add(-retention)
+
+		purged, err := userRepository.Purge(ctx, deletedBefore)
+		if err != nil {
+			return fmt.Errorf("削除済みユーザーの完全削除に失敗しました: %w", err)
+		}
+
+		if purged > 0 {
+			logging.FromContext(ctx).Info("削除済みのユーザーを完全に削除しました", "purged", purged, "deleted_before", deletedBefore)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,57 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/infra"
+)
+
+// PurgeDeletedUsers が、保持期間を過ぎた削除済みのユーザーのみを完全に削除することのテスト。
+func TestPurgeDeletedUsers(t *testing.T) {
+	ctx := context.Background()
+	clock := domain.NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	userRepository := infra.NewMemoryUserRepository()
+	for _, userID := range []string{"U1", "U2"} {
+		user := domain.User{
+			UserID:       userID,
+			Name:         "ユーザー",
+			Status:       domain.UserStatusNormal,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}
+		if err := userRepository.Put(ctx, &user); err != nil {
+			t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+		}
+	}
+	if _, err := userRepository.Delete(ctx, "U1", clock.Now()); err != nil {
+		t.Fatalf("ユーザーの削除に失敗しました: %v", err)
+	}
+
+	purge := PurgeDeletedUsers(userRepository, 1*time.Hour, clock)
+
+	// 保持期間中は（ちょうど保持期間が経過した時点でも）完全に削除しない
+	for _, d := range []time.Duration{0, 1 * time.Hour} {
+		clock.Advance(d)
+		if err := purge(ctx); err != nil {
+			t.Fatalf("ジョブがエラーを返しました: %v", err)
+		}
+		if _, err := userRepository.GetIncludingDeleted(ctx, "U1"); err != nil {
+			t.Fatalf("保持期間中の削除済みのユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+		}
+	}
+
+	// 保持期間を過ぎると完全に削除する
+	clock.Advance(time.Nanosecond)
+	if err := purge(ctx); err != nil {
+		t.Fatalf("ジョブがエラーを返しました: %v", err)
+	}
+	if _, err := userRepository.GetIncludingDeleted(ctx, "U1"); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("保持期間を過ぎた削除済みのユーザーを取得しようとしましたが、domain.ErrUserNotFound ではなく %v が返りました", err)
+	}
+	if _, err := userRepository.Get(ctx, "U2"); err != nil {
+		t.Fatalf("削除されていないユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+}
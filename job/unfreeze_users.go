@@ -0,0 +1,63 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/logging"
+)
+
+// ジョブがステータス変更の記録に残す操作者の ID。
+const SystemOperatorID = "system"
+
+// 一度に取得するユーザーの件数。
+const unfreezeBatchSize = 100
+
+// 凍結の期限が過ぎたユーザーの凍結状態を解除し、保存されているステータスを通常状態に戻すジョブを返します。
+// 期限が過ぎたかどうかは clock の現在日時で判定し、該当するユーザーのみを UserRepository.ListExpiredFrozen で取得します。
+//
+// 取得してから保存するまでの間に他の操作でユーザーが更新された場合は、そのユーザーを飛ばして次回の実行に任せます。
+func UnfreezeExpiredUsers(userRepository domain.UserRepository, clock domain.Clock) Func {
+	return func(ctx context.Context) error {
+		// 1 回の実行の中では同じ日時で判定する
+		now := clock.Now()
+		unfrozen := 0
+		exclusiveStartKey := ""
+		for {
+			users, lastEvaluatedKey, err := userRepository.ListExpiredFrozen(ctx, now, exclusiveStartKey, unfreezeBatchSize)
+			if err != nil {
+				return fmt.Errorf("凍結の期限が過ぎたユーザーの取得に失敗しました: %w", err)
+			}
+
+			for i := range users {
+				user := &users[i]
+				changed, err := user.Unfreeze(SystemOperatorID, "凍結の期限が過ぎたため、自動的に解除しました", clock)
+				if err != nil {
+					return fmt.Errorf("ユーザー %s の凍結解除に失敗しました: %w", user.UserID, err)
+				}
+				if !changed {
+					continue
+				}
+				if err := userRepository.Put(ctx, user); err != nil {
+					if errors.Is(err, domain.ErrConflict) {
+						continue
+					}
+					return fmt.Errorf("ユーザー %s の保存に失敗しました: %w", user.UserID, err)
+				}
+				unfrozen++
+			}
+
+			if lastEvaluatedKey == "" {
+				break
+			}
+			exclusiveStartKey = lastEvaluatedKey
+		}
+
+		if unfrozen > 0 {
+			logging.FromContext(ctx).Info("凍結の期限が過ぎたユーザーの凍結状態を解除しました", "unfrozen", unfrozen)
+		}
+		return nil
+	}
+}
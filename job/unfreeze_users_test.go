@@ -0,0 +1,86 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/infra"
+)
+
+// UnfreezeExpiredUsers が、凍結の期限が過ぎたユーザーのみ凍結状態を解除することのテスト。
+func TestUnfreezeExpiredUsers(t *testing.T) {
+	ctx := context.Background()
+	clock := domain.NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	oneDay := clock.Now().AddDate(0, 0, 1)
+	sevenDays := clock.Now().AddDate(0, 0, 7)
+
+	userRepository := infra.NewMemoryUserRepository()
+	for _, tc := range []struct {
+		userID string
+		until  *time.Time
+	}{
+		{"U1", &oneDay},    // 1 日後に期限が切れる
+		{"U2", &sevenDays}, // 7 日後に期限が切れる
+		{"U3", nil},        // 無期限
+	} {
+		user := domain.User{
+			UserID:       tc.userID,
+			Name:         "ユーザー",
+			Status:       domain.UserStatusNormal,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}
+		if _, err := user.Freeze("OP1", "凍結理由", tc.until, clock); err != nil {
+			t.Fatalf("ユーザーの凍結に失敗しました: %v", err)
+		}
+		if err := userRepository.Put(ctx, &user); err != nil {
+			t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+		}
+	}
+
+	// 各ユーザーのステータスが期待通りであることを確認する
+	assertStatuses := func(t *testing.T, want map[string]domain.UserStatus) {
+		t.Helper()
+		for userID, wantStatus := range want {
+			user, err := userRepository.Get(ctx, userID)
+			if err != nil {
+				t.Fatalf("ユーザー %s の取得に失敗しました: %v", userID, err)
+			}
+			if user.Status != wantStatus {
+				t.Errorf("ユーザー %s のステータスは %q のはずですが、%q になっています", userID, wantStatus, user.Status)
+			}
+		}
+	}
+
+	// 期限前は何もしない
+	if err := UnfreezeExpiredUsers(userRepository, clock)(ctx); err != nil {
+		t.Fatalf("ジョブがエラーを返しました: %v", err)
+	}
+	assertStatuses(t, map[string]domain.UserStatus{"U1": domain.UserStatusFrozen, "U2": domain.UserStatusFrozen, "U3": domain.UserStatusFrozen})
+
+	// 1 日後には U1 のみ解除される
+	clock.Advance(24 * time.Hour)
+	if err := UnfreezeExpiredUsers(userRepository, clock)(ctx); err != nil {
+		t.Fatalf("ジョブがエラーを返しました: %v", err)
+	}
+	assertStatuses(t, map[string]domain.UserStatus{"U1": domain.UserStatusNormal, "U2": domain.UserStatusFrozen, "U3": domain.UserStatusFrozen})
+
+	user, err := userRepository.Get(ctx, "U1")
+	if err != nil {
+		t.Fatalf("ユーザーの取得に失敗しました: %v", err)
+	}
+	if user.FrozenUntil != nil {
+		t.Errorf("凍結を解除したユーザーの期限は nil のはずですが、%v が残っています", *user.FrozenUntil)
+	}
+	if change := user.LastStatusChange; change == nil || change.OperatorID != SystemOperatorID || !change.ChangedAt.Equal(clock.Now()) {
+		t.Errorf("操作者 ID %q, 変更日時 %v のステータス変更の記録が残るはずですが、%+v が保存されました", SystemOperatorID, clock.Now(), change)
+	}
+
+	// 1 年後でも、無期限の凍結は解除されない
+	clock.Advance(365 * 24 * time.Hour)
+	if err := UnfreezeExpiredUsers(userRepository, clock)(ctx); err != nil {
+		t.Fatalf("ジョブがエラーを返しました: %v", err)
+	}
+	assertStatuses(t, map[string]domain.UserStatus{"U1": domain.UserStatusNormal, "U2": domain.UserStatusNormal, "U3": domain.UserStatusFrozen})
+}
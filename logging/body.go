@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+)
+
+// 伏せたフィールドの値の代わりに出力する文字列。
+const redacted = "[REDACTED]"
+
+// リクエスト・レスポンスのボディのログの設定。ユースケース毎に定義します。
+// ゼロ値はボディをログに出力しない設定です。
+type BodyLogConfig struct {
+	// ボディをログに出力するかどうか。
+	Enabled bool
+	// ログに出力するボディの最大バイト数。これを超える部分は切り詰められます。0 以下の場合は制限なしです。
+	MaxBytes int
+	// 値を伏せる JSON のフィールド名。ネストしたオブジェクトや、配列の要素のオブジェクトのフィールドも対象です。
+	RedactFields []string
+}
+
+// リクエストボディをリクエストのロガーに出力します。config.Enabled が false の場合は何もしません。
+// body は JSON にエンコードできる値（リクエストをバインドした構造体など）です。
+func LogRequestBody(ctx context.Context, config BodyLogConfig, body interface{}) {
+	logBody(ctx, config, "リクエストボディ", body)
+}
+
+// レスポンスボディをリクエストのロガーに出力します。config.Enabled が false の場合は何もしません。
+// body は JSON にエンコードできる値（レスポンスの構造体など）です。
+func LogResponseBody(ctx context.Context, config BodyLogConfig, body interface{}) {
+	logBody(ctx, config, "レスポンスボディ", body)
+}
+
+func logBody(ctx context.Context, config BodyLogConfig, message string, body interface{}) {
+	if !config.Enabled {
+		return
+	}
+	logger := FromContext(ctx)
+
+	encoded, err := redactJSON(body, config.RedactFields)
+	if err != nil {
+		logger.WarnContext(ctx, "ボディをログに出力できませんでした", "error", err)
+		return
+	}
+
+	// Note: 切り詰めは伏せた後に行う（伏せる前に切り詰めると、フィールド名が切れて伏せられない可能性があるため）。
+	size := len(encoded)
+	truncated := config.MaxBytes > 0 && size > config.MaxBytes
+	if truncated {
+		encoded = encoded[:config.MaxBytes]
+	}
+
+	logger.LogAttrs(ctx, slog.LevelInfo, message,
+		slog.String("body", string(encoded)),
+		slog.Int("body_bytes", size),
+		slog.Bool("body_truncated", truncated),
+	)
+}
+
+// body を JSON にエンコードし、fields に含まれる名前のフィールドの値を伏せて返します。
+func redactJSON(body interface{}, fields []string) ([]byte, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return encoded, nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, err
+	}
+
+	redactFields := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		redactFields[field] = true
+	}
+	return json.Marshal(redactValue(decoded, redactFields))
+}
+
+// JSON をデコードした値を再帰的にたどり、redactFields に含まれる名前のフィールドの値を伏せます。
+func redactValue(value interface{}, redactFields map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if redactFields[key] {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(child, redactFields)
+			}
+		}
+		return v
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactValue(child, redactFields)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// ボディのログのテスト。
+func TestLogBody(t *testing.T) {
+	type user struct {
+		UserID string `json:"userID"`
+		Name   string `json:"name"`
+	}
+	body := struct {
+		Users []user `json:"users"`
+		Name  string `json:"name"`
+		Next  string `json:"next"`
+	}{
+		Users: []user{{UserID: "U1", Name: "山田"}, {UserID: "U2", Name: "佐藤"}},
+		Name:  "トップレベル",
+		Next:  "C1",
+	}
+
+	testCases := []struct {
+		name          string        // テストケース名
+		config        BodyLogConfig // ボディのログの設定
+		wantLogCount  int           // 期待されるログの件数
+		wantBody      string        // 期待されるボディ（JSON として比較します）
+		wantTruncated bool          // ボディが切り詰められることが期待されるか
+	}{
+		{
+			name:         "無効の場合",
+			config:       BodyLogConfig{},
+			wantLogCount: 0,
+		},
+		{
+			name:         "伏せるフィールドがない場合",
+			config:       BodyLogConfig{Enabled: true},
+			wantLogCount: 1,
+			wantBody:     `{"users":[{"userID":"U1","name":"山田"},{"userID":"U2","name":"佐藤"}],"name":"トップレベル","next":"C1"}`,
+		},
+		{
+			name:         "ネストしたフィールドも伏せられる場合",
+			config:       BodyLogConfig{Enabled: true, RedactFields: []string{"name"}},
+			wantLogCount: 1,
+			wantBody:     `{"users":[{"userID":"U1","name":"[REDACTED]"},{"userID":"U2","name":"[REDACTED]"}],"name":"[REDACTED]","next":"C1"}`,
+		},
+		{
+			name:          "最大バイト数を超える場合",
+			config:        BodyLogConfig{Enabled: true, MaxBytes: 10},
+			wantLogCount:  1,
+			wantTruncated: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := WithLogger(context.Background(), New(&buf, "info"))
+
+			LogResponseBody(ctx, tc.config, body)
+
+			logs := parseLogs(t, &buf)
+			if len(logs) != tc.wantLogCount {
+				t.Fatalf("%d 件のログが出力されるはずですが、%d 件出力されました: %v", tc.wantLogCount, len(logs), logs)
+			}
+			if tc.wantLogCount == 0 {
+				return
+			}
+
+			gotBody, _ := logs[0]["body"].(string)
+			if tc.wantTruncated {
+				if logs[0]["body_truncated"] != true || len(gotBody) != tc.config.MaxBytes {
+					t.Errorf("ボディが %d バイトに切り詰められるはずですが、切り詰められていません: %v", tc.config.MaxBytes, logs[0])
+				}
+				return
+			}
+			var want, got interface{}
+			if err := json.Unmarshal([]byte(tc.wantBody), &want); err != nil {
+				t.Fatalf("期待されるボディのパースに失敗しました: %v", err)
+			}
+			if err := json.Unmarshal([]byte(gotBody), &got); err != nil {
+				t.Fatalf("ログのボディのパースに失敗しました: %v: %s", err, gotBody)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("期待されるボディ (-) と実際のボディ (+) が一致しませんでした:\n%s", diff)
+			}
+			if strings.Contains(buf.String(), "山田") && len(tc.config.RedactFields) > 0 {
+				t.Errorf("伏せるべき値がログに含まれています: %s", buf.String())
+			}
+		})
+	}
+}
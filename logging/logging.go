@@ -0,0 +1,85 @@
+// logging パッケージは、アプリケーション全体で使用する構造化ログ（JSON）のロガーを提供します。
+//
+// リクエストの処理中は、[Middleware] がリクエスト ID を付与したロガーをリクエストのコンテキストに格納します。
+// ユースケースやリポジトリの実装では [FromContext] でそのロガーを取り出して使用することで、
+// アクセスログと同じリクエスト ID でログを追跡できます。
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ログに出力するリクエスト ID のキー。
+const RequestIDKey = "request_id"
+
+// コンテキストにロガーを格納する際のキー。
+type contextKey struct{}
+
+// JSON 形式でログを出力するロガーを返します。
+// level は debug, info, warn, error のいずれかです（大文字小文字は区別しません）。不正な値の場合は info になります。
+func New(w io.Writer, level string) *slog.Logger {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		lvl = slog.LevelInfo
+	}
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl}))
+}
+
+// ロガーを格納したコンテキストを返します。
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// コンテキストに格納されたロガーを返します。
+// ロガーが格納されていない場合は slog.Default() を返すので、戻り値は常に nil ではありません。
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// リクエスト毎に、リクエスト ID を付与したロガーをリクエストのコンテキストに格納し、
+// リクエストの処理後にアクセスログを出力するミドルウェアを返します。
+//
+// リクエスト ID は middleware.RequestID が設定するレスポンスヘッダーから取得するので、
+// このミドルウェアは middleware.RequestID より後に登録してください。
+// また、パニックした場合もアクセスログを出力できるよう、middleware.Recover より前に登録してください。
+func Middleware(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			requestLogger := logger.With(RequestIDKey, c.Response().Header().Get(echo.HeaderXRequestID))
+			request := c.Request()
+			c.SetRequest(request.WithContext(WithLogger(request.Context(), requestLogger)))
+
+			if err := next(c); err != nil {
+				// エラーレスポンスを書き込ませて、正しいステータスコードをログに出力する
+				c.Error(err)
+			}
+
+			response := c.Response()
+			level := slog.LevelInfo
+			if response.Status >= 500 {
+				level = slog.LevelError
+			}
+			requestLogger.LogAttrs(request.Context(), level, "アクセスログ",
+				slog.String("method", request.Method),
+				slog.String("uri", request.RequestURI),
+				slog.String("route", c.Path()),
+				slog.Int("status", response.Status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.String("remote_ip", c.RealIP()),
+				slog.String("user_agent", request.UserAgent()),
+				slog.Int64("bytes_out", response.Size),
+			)
+			return nil
+		}
+	}
+}
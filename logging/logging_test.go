@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// テスト用に、JSON Lines 形式のログをパースします。
+func parseLogs(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	logs := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var log map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &log); err != nil {
+			t.Fatalf("ログのパースに失敗しました: %v: %s", err, line)
+		}
+		logs = append(logs, log)
+	}
+	return logs
+}
+
+// ミドルウェアがリクエスト ID 付きのロガーをコンテキストに格納し、アクセスログを出力することのテスト。
+func TestMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "info")
+
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(Middleware(logger))
+	e.GET("/users/:userID", func(c echo.Context) error {
+		FromContext(c.Request().Context()).Info("ユースケースのログ")
+		return echo.NewHTTPError(http.StatusNotFound, errors.New("見つかりません"))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/users/U1", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	requestID := recorder.Header().Get(echo.HeaderXRequestID)
+	if requestID == "" {
+		t.Fatalf("リクエスト ID がレスポンスヘッダーに設定されていません")
+	}
+
+	logs := parseLogs(t, &buf)
+	if len(logs) != 2 {
+		t.Fatalf("ユースケースのログとアクセスログの２件が出力されるはずですが、%d 件出力されました: %v", len(logs), logs)
+	}
+	for _, log := range logs {
+		if log[RequestIDKey] != requestID {
+			t.Errorf("ログのリクエスト ID は %q のはずですが、%v でした: %v", requestID, log[RequestIDKey], log)
+		}
+	}
+
+	accessLog := logs[1]
+	if accessLog["status"] != float64(http.StatusNotFound) {
+		t.Errorf("アクセスログのステータスコードは %d のはずですが、%v でした", http.StatusNotFound, accessLog["status"])
+	}
+	if accessLog["route"] != "/users/:userID" {
+		t.Errorf("アクセスログのルートは %q のはずですが、%v でした", "/users/:userID", accessLog["route"])
+	}
+}
+
+// コンテキストにロガーが格納されていない場合は slog.Default() が返ることのテスト。
+func TestFromContextDefault(t *testing.T) {
+	if FromContext(context.Background()) == nil {
+		t.Fatalf("ロガーが格納されていないコンテキストから nil が返りました")
+	}
+}
+
+// ログレベルの指定のテスト。
+func TestNewLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "warn")
+
+	logger.Info("出力されないログ")
+	logger.Warn("出力されるログ")
+
+	logs := parseLogs(t, &buf)
+	if len(logs) != 1 || logs[0]["msg"] != "出力されるログ" {
+		t.Fatalf("warn 以上のログのみ出力されるはずですが、以下のログが出力されました: %v", logs)
+	}
+}
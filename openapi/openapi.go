@@ -0,0 +1,134 @@
+// openapi パッケージは、OpenAPI 3.0 のドキュメントの型を提供します。
+//
+// このパッケージは JSON にエンコードするための型のみを定義しており、ドキュメントの内容は利用側（usecase パッケージ）が組み立てます。
+// 使用していない OpenAPI の機能は定義していません。
+package openapi
+
+import "strings"
+
+// OpenAPI のバージョン。
+const Version = "3.0.3"
+
+// OpenAPI ドキュメント。
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components,omitempty"`
+}
+
+// API のメタデータ。
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// パスに対する操作の一覧。
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+}
+
+// 操作（エンドポイント）。
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	// 操作に必要な権限（拡張フィールド）。
+	Permissions []string `json:"x-permissions,omitempty"`
+	// レート制限の種類（拡張フィールド）。
+	RateLimitClass string `json:"x-rate-limit-class,omitempty"`
+}
+
+// パスパラメーターやクエリパラメーター。
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// リクエストボディ。
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// レスポンス。
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// メディアタイプ毎のボディのスキーマ。
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// 再利用するスキーマの一覧。
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// スキーマ。
+type Schema struct {
+	Ref              string             `json:"$ref,omitempty"`
+	Type             string             `json:"type,omitempty"`
+	Format           string             `json:"format,omitempty"`
+	Nullable         bool               `json:"nullable,omitempty"`
+	Enum             []interface{}      `json:"enum,omitempty"`
+	MinLength        *int               `json:"minLength,omitempty"`
+	MaxLength        *int               `json:"maxLength,omitempty"`
+	Minimum          *float64           `json:"minimum,omitempty"`
+	Maximum          *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum bool               `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum bool               `json:"exclusiveMaximum,omitempty"`
+	MinItems         *int               `json:"minItems,omitempty"`
+	MaxItems         *int               `json:"maxItems,omitempty"`
+	Items            *Schema            `json:"items,omitempty"`
+	Properties       map[string]*Schema `json:"properties,omitempty"`
+	Required         []string           `json:"required,omitempty"`
+}
+
+// スキーマを参照するスキーマを返します。name は Components.Schemas のキーです。
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// パス中のパラメーターを OpenAPI の形式に変換します（例: /users/:userID → /users/{userID}）。
+func Path(echoPath string) string {
+	segments := strings.Split(echoPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// パスに操作を設定します。method は HTTP メソッド（例: GET）です。未対応のメソッドの場合は false を返します。
+func (item *PathItem) SetOperation(method string, operation *Operation) bool {
+	switch method {
+	case "GET":
+		item.Get = operation
+	case "PUT":
+		item.Put = operation
+	case "POST":
+		item.Post = operation
+	case "DELETE":
+		item.Delete = operation
+	case "PATCH":
+		item.Patch = operation
+	default:
+		return false
+	}
+	return true
+}
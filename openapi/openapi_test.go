@@ -0,0 +1,23 @@
+package openapi
+
+import "testing"
+
+// パス中のパラメーターが OpenAPI の形式に変換されることのテスト。
+func TestPath(t *testing.T) {
+	testCases := []struct {
+		echoPath string // echo の形式のパス
+		want     string // 期待される OpenAPI の形式のパス
+	}{
+		{echoPath: "/users", want: "/users"},
+		{echoPath: "/users/:userID", want: "/users/{userID}"},
+		{echoPath: "/users/:userID/name", want: "/users/{userID}/name"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.echoPath, func(t *testing.T) {
+			if got := Path(tc.echoPath); got != tc.want {
+				t.Errorf("期待されるパスは %s ですが、%s が返りました", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ChangeUserName ユースケースのリクエスト。
+type ChangeUserNameRequest struct {
+	// ユーザー ID。必須で、1 文字以上 100 文字以下です。
+	UserID string `param:"userID"`
+	// 変更後の名前。必須で、1 文字以上 100 文字以下です。
+	Name string `json:"name"`
+	// If-Match ヘッダー。省略可能で、指定した場合はユーザーの ETag と一致しなければ PreconditionFailed エラーを返します。
+	IfMatch string `header:"If-Match" json:"-"`
+}
+
+func (request *ChangeUserNameRequest) validate() error {
+	return validateStruct(request, request.fieldRules())
+}
+
+func (request *ChangeUserNameRequest) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&request.UserID,
+			required(msgUserIDRequired),
+			runeLength(1, 100, msgUserIDLength),
+		),
+		field(&request.Name,
+			required(msgNameRequired),
+			runeLength(1, 100, msgNameLength),
+		),
+	}
+}
+
+// ChangeUserName ユースケースの仕様。
+var changeUserNameSpec = &Spec{
+	Name:           "ChangeUserName",
+	Method:         http.MethodPut,
+	Path:           "/users/:userID/name",
+	Summary:        "ユーザーの名前を変更します",
+	Request:        &ChangeUserNameRequest{},
+	SuccessStatus:  http.StatusNoContent,
+	ErrorCodes:     []string{ErrorCodeBadRequest, ErrorCodeUserNotFound, ErrorCodeUserFrozen, ErrorCodeUserClosed, ErrorCodeUserConflict, ErrorCodePreconditionFailed, ErrorCodeInternalServerError},
+	Permissions:    []Permission{PermissionWriteUsers},
+	RateLimitClass: RateLimitClassWrite,
+	BodyLog:        userBodyLog,
+}
+
+// ChangeUserName ユースケース。ユーザーの名前を変更します。
+//   - リクエスト: [ChangeUserNameRequest]
+//   - レスポンス: なし（HTTP ステータスコード 204）
+//
+// 成功した場合は、更新後のユーザーの ETag を ETag ヘッダーに設定します。
+// If-Match ヘッダーを指定すると、ユーザーがその ETag から更新されていない場合のみ実行します。
+//
+// このユースケースは、以下のエラーコードを返します。
+//   - BadRequest: リクエストが不正な場合。
+//   - UserNotFound: ユーザーが見つからなかった場合。
+//   - UserConflict: ユーザーを取得してから保存するまでの間に、他の操作によってユーザーが更新された場合。
+//   - PreconditionFailed: If-Match ヘッダーがユーザーの ETag と一致しない場合。
+//   - UserFrozen: ユーザーが凍結状態のため、名前を変更できない場合。
+//   - UserClosed: ユーザーが退会済みのため、名前を変更できない場合。
+//   - InternalServerError: サーバーエラーが発生した場合。
+func ChangeUserName(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error {
+	return handle(c, changeUserNameSpec, func(c echo.Context, request *ChangeUserNameRequest) (*noContent, error) {
+		ctx := c.Request().Context()
+
+		user, err := userRepository.Get(ctx, request.UserID)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return nil, userNotFound(c, err)
+			}
+			return nil, internalServerError(c, msgGetUserFailed, err)
+		}
+
+		if !matchesIfMatch(request.IfMatch, userETag(user)) {
+			return nil, preconditionFailed(c, fmt.Errorf("If-Match %s がユーザーの ETag %s と一致しません", request.IfMatch, userETag(user)))
+		}
+
+		changed, err := user.ChangeName(request.Name, clock)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserFrozen) {
+				return nil, newErrorResponse(c, ErrorCodeUserFrozen, msgUserFrozenNameChange, err)
+			}
+			if errors.Is(err, domain.ErrUserClosed) {
+				return nil, newErrorResponse(c, ErrorCodeUserClosed, msgUserClosedNameChange, err)
+			}
+			return nil, internalServerError(c, msgChangeUserNameFailed, err)
+		}
+
+		// 何も変わらない場合は、バージョン（ETag）が変わらないように保存しない
+		if changed {
+			if err := userRepository.Put(ctx, user); err != nil {
+				return nil, saveUserFailed(c, err)
+			}
+		}
+
+		c.Response().Header().Set(headerETag, userETag(user))
+		return &noContent{}, nil
+	})
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/infra"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ChangeUserName ユースケースのテスト用の echo.Context を作成します。
+func newChangeUserNameContext(userID string, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodPut, "/users/:userID/name", strings.NewReader(body))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+	c.SetParamNames("userID")
+	c.SetParamValues(userID)
+	return c, recorder
+}
+
+// ChangeUserName ユースケースの正常系のテスト。
+func TestChangeUserNameOK(t *testing.T) {
+	ctx := context.Background()
+	userRepository := infra.NewMemoryUserRepository()
+	user := domain.User{
+		UserID:       "U1",
+		Name:         "変更前",
+		Status:       domain.UserStatusNormal,
+		RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := userRepository.Put(ctx, &user); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+
+	c, recorder := newChangeUserNameContext("U1", `{"name": "変更後"}`)
+	if err := ChangeUserName(c, userRepository, domain.SystemClock); err != nil {
+		t.Fatalf("ユースケースがエラーを返しました: %v", err)
+	}
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNoContent, recorder.Code)
+	}
+
+	savedUser, err := userRepository.Get(ctx, "U1")
+	if err != nil {
+		t.Fatalf("ユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	if savedUser.Name != "変更後" {
+		t.Errorf("ユーザーの名前は %q に変更されるはずですが、%q となっています", "変更後", savedUser.Name)
+	}
+	if etag := recorder.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf("ETag ヘッダーは更新後のバージョンの %q のはずですが、%q が返りました", `"2"`, etag)
+	}
+}
+
+// ChangeUserName ユースケースの If-Match ヘッダーのテスト。
+func TestChangeUserNameIfMatch(t *testing.T) {
+	ctx := context.Background()
+	userRepository := infra.NewMemoryUserRepository()
+	user := domain.User{
+		UserID:       "U1",
+		Name:         "変更前",
+		Status:       domain.UserStatusNormal,
+		RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := userRepository.Put(ctx, &user); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+
+	// 取得した時点の ETag を指定すると変更できる
+	c, recorder := newChangeUserNameContext("U1", `{"name": "変更後"}`)
+	c.Request().Header.Set("If-Match", `"1"`)
+	if err := ChangeUserName(c, userRepository, domain.SystemClock); err != nil {
+		t.Fatalf("ユースケースがエラーを返しました: %v", err)
+	}
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNoContent, recorder.Code)
+	}
+
+	// 同じ ETag をもう一度指定すると、ユーザーが更新されているため変更できない
+	c, _ = newChangeUserNameContext("U1", `{"name": "さらに変更後"}`)
+	c.Request().Header.Set("If-Match", `"1"`)
+	err := ChangeUserName(c, userRepository, domain.SystemClock)
+	if err == nil {
+		t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+	}
+	statusCode, errorResponse := ParseErrorResponse(t, err)
+	if statusCode != http.StatusPreconditionFailed || errorResponse.Code != "PreconditionFailed" {
+		t.Errorf("期待されるエラーは %d PreconditionFailed ですが、%d %s が返りました", http.StatusPreconditionFailed, statusCode, errorResponse.Code)
+	}
+
+	savedUser, err := userRepository.Get(ctx, "U1")
+	if err != nil {
+		t.Fatalf("ユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	if savedUser.Name != "変更後" {
+		t.Errorf("If-Match が一致しない場合は名前を変更しないはずですが、%q に変更されています", savedUser.Name)
+	}
+}
+
+// ChangeUserName ユースケースのエラー系のテスト。
+func TestChangeUserNameError(t *testing.T) {
+	frozenUser := func(ctx context.Context, userID string) (*domain.User, error) {
+		return &domain.User{
+			UserID:       userID,
+			Name:         "変更前",
+			Status:       domain.UserStatusFrozen,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}, nil
+	}
+	closedUser := func(ctx context.Context, userID string) (*domain.User, error) {
+		return &domain.User{
+			UserID:       userID,
+			Name:         "変更前",
+			Status:       domain.UserStatusClosed,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}, nil
+	}
+	normalUser := func(ctx context.Context, userID string) (*domain.User, error) {
+		return &domain.User{
+			UserID:       userID,
+			Name:         "変更前",
+			Status:       domain.UserStatusNormal,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Version:      1,
+		}, nil
+	}
+	notFound := func(ctx context.Context, userID string) (*domain.User, error) {
+		return nil, domain.ErrUserNotFound
+	}
+	conflict := func(ctx context.Context, user *domain.User) error {
+		return domain.ErrConflict
+	}
+
+	testCases := []struct {
+		name           string                                                         // テストケース名
+		userID         string                                                         // 名前を変更しようとするユーザーの ID
+		body           string                                                         // リクエストボディ
+		get            func(ctx context.Context, userID string) (*domain.User, error) // リポジトリの Get
+		put            func(ctx context.Context, user *domain.User) error             // リポジトリの Put
+		wantStatusCode int                                                            // 期待される HTTP ステータスコード
+		wantErrorCode  string                                                         // 期待されるエラーコード
+	}{
+		{
+			name:           "名前が空文字列の場合",
+			userID:         "U1",
+			body:           `{"name": ""}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "BadRequest",
+		},
+		{
+			name:           "ユーザーが見つからない場合",
+			userID:         "U1",
+			body:           `{"name": "変更後"}`,
+			get:            notFound,
+			wantStatusCode: http.StatusNotFound,
+			wantErrorCode:  "UserNotFound",
+		},
+		{
+			name:           "ユーザーが凍結状態の場合",
+			userID:         "U1",
+			body:           `{"name": "変更後"}`,
+			get:            frozenUser,
+			wantStatusCode: http.StatusConflict,
+			wantErrorCode:  "UserFrozen",
+		},
+		{
+			name:           "ユーザーが退会済みの場合",
+			userID:         "U1",
+			body:           `{"name": "変更後"}`,
+			get:            closedUser,
+			wantStatusCode: http.StatusConflict,
+			wantErrorCode:  "UserClosed",
+		},
+		{
+			name:           "取得してから保存するまでの間に他の操作でユーザーが更新された場合",
+			userID:         "U1",
+			body:           `{"name": "変更後"}`,
+			get:            normalUser,
+			put:            conflict,
+			wantStatusCode: http.StatusConflict,
+			wantErrorCode:  "UserConflict",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepository := &MockUserRepository{get: tc.get, put: tc.put}
+
+			c, _ := newChangeUserNameContext(tc.userID, tc.body)
+			err := ChangeUserName(c, userRepository, domain.SystemClock)
+			if err == nil {
+				t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+			}
+
+			statusCode, errorResponse := ParseErrorResponse(t, err)
+			if statusCode != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, statusCode)
+			}
+			if errorResponse.Code != tc.wantErrorCode {
+				t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", tc.wantErrorCode, errorResponse.Code)
+			}
+		})
+	}
+}
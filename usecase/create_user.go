@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"net/http"
+	"net/url"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateUser ユースケースのリクエスト。
+type CreateUserRequest struct {
+	// 名前。必須で、1 文字以上 100 文字以下です。
+	Name string `json:"name"`
+}
+
+func (request *CreateUserRequest) validate() error {
+	return validateStruct(request, request.fieldRules())
+}
+
+func (request *CreateUserRequest) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&request.Name,
+			required(msgNameRequired),
+			runeLength(1, 100, msgNameLength),
+		),
+	}
+}
+
+// CreateUser ユースケースのレスポンス。
+type CreateUserResponse struct {
+	// 作成されたユーザーのユーザー ID。必須で、1 文字以上 100 文字以下です。
+	UserID string `json:"userID"`
+}
+
+func (response *CreateUserResponse) validate() error {
+	return validateStruct(response, response.fieldRules())
+}
+
+func (response *CreateUserResponse) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&response.UserID,
+			required(msgUserIDRequired),
+			runeLength(1, 100, msgUserIDLength),
+		),
+	}
+}
+
+// CreateUser ユースケースの仕様。
+var createUserSpec = &Spec{
+	Name:           "CreateUser",
+	Method:         http.MethodPost,
+	Path:           "/users",
+	Summary:        "ユーザーを作成します",
+	Description:    "成功した場合は Location ヘッダーに作成されたユーザーの URL を設定します。",
+	Request:        &CreateUserRequest{},
+	Response:       &CreateUserResponse{},
+	SuccessStatus:  http.StatusCreated,
+	ErrorCodes:     []string{ErrorCodeBadRequest, ErrorCodeInternalServerError},
+	Permissions:    []Permission{PermissionWriteUsers},
+	RateLimitClass: RateLimitClassWrite,
+	BodyLog:        userBodyLog,
+}
+
+// CreateUser ユースケース。ユーザーを作成します。
+//   - リクエスト: [CreateUserRequest]
+//   - レスポンス: [CreateUserResponse]
+//
+// 成功した場合は HTTP ステータスコード 201 を返し、Location ヘッダーに作成されたユーザーの URL を、ETag ヘッダーにユーザーの ETag を設定します。
+//
+// このユースケースは、以下のエラーコードを返します。
+//   - BadRequest: リクエストが不正な場合。
+//   - InternalServerError: サーバーエラーが発生した場合。
+func CreateUser(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error {
+	return handle(c, createUserSpec, func(c echo.Context, request *CreateUserRequest) (*CreateUserResponse, error) {
+		user := domain.NewUser(request.Name, clock)
+		if err := userRepository.Put(c.Request().Context(), &user); err != nil {
+			return nil, saveUserFailed(c, err)
+		}
+
+		c.Response().Header().Set(echo.HeaderLocation, "/users/"+url.PathEscape(user.UserID))
+		c.Response().Header().Set(headerETag, userETag(&user))
+		return &CreateUserResponse{
+			UserID: user.UserID,
+		}, nil
+	})
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/infra"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateUser ユースケースの正常系のテスト。
+func TestCreateUserOK(t *testing.T) {
+	userRepository := infra.NewMemoryUserRepository()
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name": "ユーザー１"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+
+	if err := CreateUser(c, userRepository, domain.SystemClock); err != nil {
+		t.Fatalf("ユースケースがエラーを返しました: %v", err)
+	}
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusCreated, recorder.Code)
+	}
+
+	var response CreateUserResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("レスポンスボディのデコードに失敗しました: %v", err)
+	}
+	if location := recorder.Header().Get(echo.HeaderLocation); location != "/users/"+response.UserID {
+		t.Errorf("期待される Location ヘッダーは %q ですが、%q が返りました", "/users/"+response.UserID, location)
+	}
+
+	// レスポンスのユーザー ID でユーザーが保存されていることを確認
+	savedUser, err := userRepository.Get(context.Background(), response.UserID)
+	if err != nil {
+		t.Fatalf("作成されたユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	if savedUser.Name != "ユーザー１" {
+		t.Errorf("名前が %q のユーザーが保存されるはずですが、%q のユーザーが保存されました", "ユーザー１", savedUser.Name)
+	}
+	if savedUser.Status != domain.UserStatusNormal {
+		t.Errorf("ステータスが %q のユーザーが保存されるはずですが、%q のユーザーが保存されました", domain.UserStatusNormal, savedUser.Status)
+	}
+}
+
+// CreateUser ユースケースのリクエストのバリデーションのテスト。
+func TestCreateUserBadRequest(t *testing.T) {
+	userRepository := &MockUserRepository{}
+
+	testCases := []struct {
+		name string // テストケース名
+		body string // リクエストボディ
+	}{
+		{name: "名前が省略された場合", body: `{}`},
+		{name: "名前が空文字列の場合", body: `{"name": ""}`},
+		{name: "名前が 101 文字の場合", body: `{"name": "` + strings.Repeat("あ", 101) + `"}`},
+		{name: "JSON が不正な場合", body: `{`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tc.body))
+			request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c := e.NewContext(request, nil)
+
+			err := CreateUser(c, userRepository, domain.SystemClock)
+			if err == nil {
+				t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+			}
+
+			statusCode, errorResponse := ParseErrorResponse(t, err)
+			if statusCode != http.StatusBadRequest {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusBadRequest, statusCode)
+			}
+			if errorResponse.Code != "BadRequest" {
+				t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", "BadRequest", errorResponse.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DeleteUser ユースケースのリクエスト。
+type DeleteUserRequest struct {
+	// ユーザー ID。必須で、1 文字以上 100 文字以下です。
+	UserID string `param:"userID"`
+	// true の場合、ユーザーが見つからなければ UserNotFound エラーを返します。省略可能で、省略した場合は false です。
+	MustExist bool `query:"mustExist"`
+	// If-Match ヘッダー。省略可能で、指定した場合はユーザーの ETag と一致しなければ PreconditionFailed エラーを返します。
+	IfMatch string `header:"If-Match" json:"-"`
+}
+
+func (request *DeleteUserRequest) validate() error {
+	return validateStruct(request, request.fieldRules())
+}
+
+func (request *DeleteUserRequest) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&request.UserID,
+			required(msgUserIDRequired),
+			runeLength(1, 100, msgUserIDLength),
+		),
+	}
+}
+
+// DeleteUser ユースケースの仕様。
+var deleteUserSpec = &Spec{
+	Name:           "DeleteUser",
+	Method:         http.MethodDelete,
+	Path:           "/users/:userID",
+	Summary:        "ユーザーを削除します",
+	Description:    "削除は論理削除で、完全に削除されるまでの保持期間中は RestoreUser で復元できます。この操作は冪等です。つまり、ユーザーが見つからない場合も成功します。ただし、mustExist が true の場合は、ユーザーが見つからなければ UserNotFound エラーを返します。If-Match を指定した場合、ETag を確認してから削除するまでの間に他の操作でユーザーが更新されると UserConflict エラーを返します。",
+	Request:        &DeleteUserRequest{},
+	SuccessStatus:  http.StatusNoContent,
+	ErrorCodes:     []string{ErrorCodeBadRequest, ErrorCodeUserNotFound, ErrorCodeUserConflict, ErrorCodePreconditionFailed, ErrorCodeInternalServerError},
+	Permissions:    []Permission{PermissionWriteUsers},
+	RateLimitClass: RateLimitClassWrite,
+}
+
+// DeleteUser ユースケース。ユーザーを削除します。
+//   - リクエスト: [DeleteUserRequest]
+//   - レスポンス: なし（HTTP ステータスコード 204）
+//
+// 削除は論理削除です。削除済みのユーザーは取得や一覧に含まれなくなりますが、
+// 完全に削除されるまでの保持期間中は [RestoreUser] で復元できます。
+//
+// この操作は冪等です。つまり、ユーザーが見つからない（あるいは削除済みの）場合も成功します。
+// ただし、リクエストの mustExist が true の場合は、ユーザーが見つからなければ UserNotFound エラーを返します。
+//
+// If-Match ヘッダーを指定すると、ユーザーがその ETag から更新されていない場合のみ削除します。
+// この場合、ユーザーが見つからなければ（mustExist が true の場合を除き）PreconditionFailed エラーを返します。
+// ETag を確認してから削除するまでの間に他の操作でユーザーが更新された場合は、削除せずに UserConflict エラーを返します。
+//
+// このユースケースは、以下のエラーコードを返します。
+//   - BadRequest: リクエストが不正な場合。
+//   - UserNotFound: mustExist が true で、ユーザーが見つからなかった場合。
+//   - UserConflict: If-Match ヘッダーを指定し、ETag を確認してから削除するまでの間に、他の操作によってユーザーが更新された場合。
+//   - PreconditionFailed: If-Match ヘッダーがユーザーの ETag と一致しない場合。
+//   - InternalServerError: サーバーエラーが発生した場合。
+func DeleteUser(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error {
+	return handle(c, deleteUserSpec, func(c echo.Context, request *DeleteUserRequest) (*noContent, error) {
+		ctx := c.Request().Context()
+
+		if request.IfMatch != "" {
+			user, err := userRepository.Get(ctx, request.UserID)
+			if err != nil {
+				if !errors.Is(err, domain.ErrUserNotFound) {
+					return nil, internalServerError(c, msgGetUserFailed, err)
+				}
+				if request.MustExist {
+					return nil, userNotFound(c, err)
+				}
+				return nil, preconditionFailed(c, fmt.Errorf("If-Match %s を指定しましたが、ユーザーが見つかりません: %w", request.IfMatch, err))
+			}
+			if !matchesIfMatch(request.IfMatch, userETag(user)) {
+				return nil, preconditionFailed(c, fmt.Errorf("If-Match %s がユーザーの ETag %s と一致しません", request.IfMatch, userETag(user)))
+			}
+
+			// 確認したバージョンのまま削除するため、Put で保存する（間に更新されていれば ErrConflict になる）
+			user.Delete(clock)
+			if err := userRepository.Put(ctx, user); err != nil {
+				return nil, saveUserFailed(c, err)
+			}
+			return &noContent{}, nil
+		}
+
+		deleted, err := userRepository.Delete(ctx, request.UserID, clock.Now())
+		if err != nil {
+			return nil, internalServerError(c, msgDeleteUserFailed, err)
+		}
+		if !deleted && request.MustExist {
+			return nil, userNotFound(c, domain.ErrUserNotFound)
+		}
+
+		return &noContent{}, nil
+	})
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DeleteUser ユースケースの正常系のテスト。
+func TestDeleteUserOK(t *testing.T) {
+	testCases := []struct {
+		query   string // クエリ文字列
+		deleted bool   // リポジトリが返す deleted
+	}{
+		{query: "", deleted: true},
+		{query: "", deleted: false},
+		{query: "?mustExist=false", deleted: false},
+		{query: "?mustExist=true", deleted: true},
+	}
+
+	clock := domain.NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("query=%s,deleted=%t", tc.query, tc.deleted), func(t *testing.T) {
+			userRepository := &MockUserRepository{
+				delete: func(ctx context.Context, userID string, deletedAt time.Time) (bool, error) {
+					if userID != "U1" {
+						t.Fatalf("ユーザー ID が %q ではなく %q のユーザーを削除しようとしました", "U1", userID)
+					}
+					if !deletedAt.Equal(clock.Now()) {
+						t.Errorf("削除日時は時計の現在日時 %v のはずですが、%v が指定されました", clock.Now(), deletedAt)
+					}
+					return tc.deleted, nil
+				},
+			}
+
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodDelete, "/users/:userID"+tc.query, nil)
+			recorder := httptest.NewRecorder()
+			c := e.NewContext(request, recorder)
+			c.SetParamNames("userID")
+			c.SetParamValues("U1")
+
+			if err := DeleteUser(c, userRepository, clock); err != nil {
+				t.Fatalf("ユースケースがエラーを返しました: %v", err)
+			}
+			if recorder.Code != http.StatusNoContent {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNoContent, recorder.Code)
+			}
+		})
+	}
+}
+
+// DeleteUser ユースケースで mustExist が true かつユーザーが見つからない場合のテスト。
+func TestDeleteUserUserNotFound(t *testing.T) {
+	userRepository := &MockUserRepository{
+		delete: func(ctx context.Context, userID string, deletedAt time.Time) (bool, error) {
+			return false, nil
+		},
+	}
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodDelete, "/users/:userID?mustExist=true", nil)
+	c := e.NewContext(request, nil)
+	c.SetParamNames("userID")
+	c.SetParamValues("U1")
+
+	err := DeleteUser(c, userRepository, domain.SystemClock)
+	if err == nil {
+		t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+	}
+
+	statusCode, errorResponse := ParseErrorResponse(t, err)
+	if statusCode != http.StatusNotFound {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNotFound, statusCode)
+	}
+	if errorResponse.Code != "UserNotFound" {
+		t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", "UserNotFound", errorResponse.Code)
+	}
+}
+
+// DeleteUser ユースケースの If-Match ヘッダーのテスト。
+func TestDeleteUserIfMatch(t *testing.T) {
+	testCases := []struct {
+		name           string // テストケース名
+		ifMatch        string // If-Match ヘッダー
+		found          bool   // リポジトリにユーザーが存在するかどうか
+		putErr         error  // リポジトリの Put が返すエラー
+		wantStatusCode int    // 期待される HTTP ステータスコード
+		wantErrorCode  string // 期待されるエラーコード（エラーでない場合は空文字列）
+	}{
+		{name: "ETag が一致する場合", ifMatch: `"2"`, found: true, wantStatusCode: http.StatusNoContent},
+		{name: "* の場合", ifMatch: "*", found: true, wantStatusCode: http.StatusNoContent},
+		{name: "ETag が一致しない場合", ifMatch: `"1"`, found: true, wantStatusCode: http.StatusPreconditionFailed, wantErrorCode: "PreconditionFailed"},
+		{name: "弱い ETag の場合", ifMatch: `W/"2"`, found: true, wantStatusCode: http.StatusPreconditionFailed, wantErrorCode: "PreconditionFailed"},
+		{name: "ユーザーが見つからない場合", ifMatch: `"2"`, found: false, wantStatusCode: http.StatusPreconditionFailed, wantErrorCode: "PreconditionFailed"},
+		{name: "ETag の確認後に他の操作で更新された場合", ifMatch: `"2"`, found: true, putErr: domain.ErrConflict, wantStatusCode: http.StatusConflict, wantErrorCode: "UserConflict"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			deleted := false
+			userRepository := &MockUserRepository{
+				get: func(ctx context.Context, userID string) (*domain.User, error) {
+					if !tc.found {
+						return nil, domain.ErrUserNotFound
+					}
+					return &domain.User{UserID: userID, Name: "ユーザー", Status: domain.UserStatusNormal, Version: 2}, nil
+				},
+				// If-Match を指定した場合は、確認したバージョンのまま Put で削除する
+				put: func(ctx context.Context, user *domain.User) error {
+					if tc.putErr != nil {
+						return tc.putErr
+					}
+					if user.Version != 2 || !user.IsDeleted() {
+						t.Errorf("バージョン 2 の削除済みのユーザーが保存されるはずですが、%+v が保存されました", *user)
+					}
+					deleted = true
+					return nil
+				},
+			}
+
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodDelete, "/users/:userID", nil)
+			request.Header.Set("If-Match", tc.ifMatch)
+			recorder := httptest.NewRecorder()
+			c := e.NewContext(request, recorder)
+			c.SetParamNames("userID")
+			c.SetParamValues("U1")
+
+			err := DeleteUser(c, userRepository, domain.SystemClock)
+			if tc.wantErrorCode == "" {
+				if err != nil {
+					t.Fatalf("ユースケースがエラーを返しました: %v", err)
+				}
+				if recorder.Code != tc.wantStatusCode {
+					t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, recorder.Code)
+				}
+				if !deleted {
+					t.Errorf("ユーザーが削除されませんでした")
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+			}
+			statusCode, errorResponse := ParseErrorResponse(t, err)
+			if statusCode != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, statusCode)
+			}
+			if errorResponse.Code != tc.wantErrorCode {
+				t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", tc.wantErrorCode, errorResponse.Code)
+			}
+			if deleted {
+				t.Errorf("If-Match ヘッダーの条件を満たさない場合は削除しないはずですが、削除しました")
+			}
+		})
+	}
+}
+
+// DeleteUser ユースケースのリクエストのバリデーションのテスト。
+func TestDeleteUserBadRequest(t *testing.T) {
+	userRepository := &MockUserRepository{}
+
+	testCases := []struct {
+		userID string // 削除しようとするユーザーの ID
+		query  string // クエリ文字列
+	}{
+		{userID: "", query: ""},
+		{userID: "U1", query: "?mustExist=abc"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("userID=%s,query=%s", tc.userID, tc.query), func(t *testing.T) {
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodDelete, "/users/:userID"+tc.query, nil)
+			c := e.NewContext(request, nil)
+			c.SetParamNames("userID")
+			c.SetParamValues(tc.userID)
+
+			err := DeleteUser(c, userRepository, domain.SystemClock)
+			if err == nil {
+				t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+			}
+
+			statusCode, errorResponse := ParseErrorResponse(t, err)
+			if statusCode != http.StatusBadRequest {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusBadRequest, statusCode)
+			}
+			if errorResponse.Code != "BadRequest" {
+				t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", "BadRequest", errorResponse.Code)
+			}
+		})
+	}
+}
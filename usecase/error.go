@@ -1,9 +1,16 @@
 package usecase
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"sort"
+	"strings"
 
+	"nekonoshiri/go-echo-sample/domain"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/labstack/echo/v4"
 )
 
@@ -13,10 +20,26 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 	// エラーコード。これは HTTP ステータスコードではなく、ユースケース毎に定義されるコードです。
 	Code string `json:"code"`
+	// リクエスト ID。エラーハンドラー（[NewHTTPErrorHandler]）が設定します。
+	RequestID string `json:"requestID,omitempty"`
+	// 内部のエラーの詳細。開発モードの場合のみ、エラーハンドラーが設定します。
+	Debug string `json:"debug,omitempty"`
+	// リクエストのバリデーションエラーの場合の、フィールド毎のエラー。フィールド名の昇順です。
+	Details []ErrorDetail `json:"details,omitempty"`
 	// ラップしているエラー。レスポンス JSON には含まれません。
 	Err error `json:"-"`
 }
 
+// リクエストのフィールド毎のバリデーションエラー。
+type ErrorDetail struct {
+	// フィールド名。リクエストの JSON のキー、パスパラメーター名、あるいはクエリパラメーター名です。
+	Field string `json:"field"`
+	// バリデーションのルールを表すコード（例: validation_required）。
+	Code string `json:"code"`
+	// エラーメッセージ。
+	Message string `json:"message"`
+}
+
 func (e *ErrorResponse) Error() string {
 	return fmt.Sprintf("%s %s: %v", e.Code, e.Message, e.Err)
 }
@@ -25,21 +48,202 @@ func (e *ErrorResponse) Unwrap() error {
 	return e.Err
 }
 
-// クライアントにエラーレスポンスを返します。
-func newErrorResponse(c echo.Context, httpStatusCode int, errorCode string, errorMessage string, err error) error {
-	return echo.NewHTTPError(httpStatusCode, &ErrorResponse{
+// エラーコード。ErrorResponse の Code に設定されます。
+// エラーコードを追加する場合は、errorCodes にも追加してください。
+const (
+	// リクエストが不正です。
+	ErrorCodeBadRequest = "BadRequest"
+	// ユーザーが見つかりません。
+	ErrorCodeUserNotFound = "UserNotFound"
+	// ユーザーが凍結状態のため、操作できません。
+	ErrorCodeUserFrozen = "UserFrozen"
+	// ユーザーが退会済みのため、操作できません。
+	ErrorCodeUserClosed = "UserClosed"
+	// ユーザーの現在のステータスからは、そのステータスに変更できません。
+	ErrorCodeInvalidStatusTransition = "InvalidStatusTransition"
+	// ユーザーが他の操作によって更新されたため、保存できません。
+	ErrorCodeUserConflict = "UserConflict"
+	// ユーザーが If-Match ヘッダーの条件を満たしません（クライアントが取得した後に更新されています）。
+	ErrorCodePreconditionFailed = "PreconditionFailed"
+	// ルートが見つかりません（ユースケースではなく、ルーティングで発生します）。
+	ErrorCodeNotFound = "NotFound"
+	// メソッドが許可されていません（ユースケースではなく、ルーティングで発生します）。
+	ErrorCodeMethodNotAllowed = "MethodNotAllowed"
+	// リクエストが多すぎます（ユースケースではなく、レート制限で発生します）。
+	ErrorCodeTooManyRequests = "TooManyRequests"
+	// サーバーエラーが発生しました。
+	ErrorCodeInternalServerError = "InternalServerError"
+)
+
+// エラーコードの定義。
+type errorCode struct {
+	// このエラーコードを返す際の HTTP ステータスコード。
+	httpStatus int
+	// エラーの種類を表す短い説明のメッセージ ID。RFC 7807 の title や、ユースケース以外で発生したエラーのメッセージに使用します。
+	titleID string
+}
+
+// エラーコードの一覧。
+var errorCodes = map[string]errorCode{
+	ErrorCodeBadRequest:              {http.StatusBadRequest, msgBadRequest},
+	ErrorCodeUserNotFound:            {http.StatusNotFound, msgUserNotFound},
+	ErrorCodeUserFrozen:              {http.StatusConflict, msgUserFrozen},
+	ErrorCodeUserClosed:              {http.StatusConflict, msgUserClosed},
+	ErrorCodeInvalidStatusTransition: {http.StatusConflict, msgInvalidStatusTransition},
+	ErrorCodeUserConflict:            {http.StatusConflict, msgUserConflict},
+	ErrorCodePreconditionFailed:      {http.StatusPreconditionFailed, msgPreconditionFailed},
+	ErrorCodeNotFound:                {http.StatusNotFound, msgNotFound},
+	ErrorCodeMethodNotAllowed:        {http.StatusMethodNotAllowed, msgMethodNotAllowed},
+	ErrorCodeTooManyRequests:         {http.StatusTooManyRequests, msgTooManyRequests},
+	ErrorCodeInternalServerError:     {http.StatusInternalServerError, msgInternalServerError},
+}
+
+// エラーコードに対応する HTTP ステータスコードを返します。
+// 未知のエラーコードの場合は 500 (Internal Server Error) を返します。
+func HTTPStatus(errorCode string) int {
+	if code, ok := errorCodes[errorCode]; ok {
+		return code.httpStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// エラーコードに対応するエラーの種類の説明を、リクエストの言語で返します。
+// 未知のエラーコードの場合は InternalServerError のものを返します。
+func errorTitle(c echo.Context, errorCode string) string {
+	if code, ok := errorCodes[errorCode]; ok {
+		return localize(c, code.titleID)
+	}
+	return localize(c, errorCodes[ErrorCodeInternalServerError].titleID)
+}
+
+// クライアントにエラーレスポンスを返します。HTTP ステータスコードはエラーコードから決まります（[HTTPStatus]）。
+// メッセージはメッセージ ID で指定し、リクエストの言語（Accept-Language ヘッダー）のメッセージに変換します。
+// エラーレスポンスの出力とログへの出力は、エラーハンドラー（[NewHTTPErrorHandler]）が行います。
+func newErrorResponse(c echo.Context, errorCode string, messageID string, err error) error {
+	return echo.NewHTTPError(HTTPStatus(errorCode), &ErrorResponse{
 		Code:    errorCode,
-		Message: errorMessage,
+		Message: localize(c, messageID),
 		Err:     err,
 	})
 }
 
-// クライアントに不正なリクエストエラー（HTTP ステータスコード 400, エラーコード BadRequest）を返します。
-func badRequest(c echo.Context, message string, err error) error {
-	return newErrorResponse(c, http.StatusBadRequest, "BadRequest", message, err)
+// クライアントに不正なリクエストエラー（エラーコード BadRequest）を返します。
+func badRequest(c echo.Context, messageID string, err error) error {
+	return newErrorResponse(c, ErrorCodeBadRequest, messageID, err)
+}
+
+// クライアントにリクエストのバリデーションエラー（エラーコード BadRequest）を返します。
+// request はバリデーションしたリクエストの構造体へのポインターで、エラーレスポンスの details のフィールド名を決めるのに使用します。
+// バリデーションのエラーメッセージはメッセージ ID とみなし、リクエストの言語のメッセージに変換します。
+func invalidRequest(c echo.Context, request interface{}, errs validation.Errors) error {
+	details := errorDetails(c, requestFieldNames(request), "", errs)
+	summaries := make([]string, 0, len(details))
+	for _, detail := range details {
+		summaries = append(summaries, detail.Field+": "+detail.Message)
+	}
+
+	return echo.NewHTTPError(HTTPStatus(ErrorCodeBadRequest), &ErrorResponse{
+		Code:    ErrorCodeBadRequest,
+		Message: localize(c, msgBadRequestWithErrors, strings.Join(summaries, "; ")),
+		Err:     errs,
+		Details: details,
+	})
+}
+
+// クライアントにユーザーが見つからないエラー（エラーコード UserNotFound）を返します。
+func userNotFound(c echo.Context, err error) error {
+	return newErrorResponse(c, ErrorCodeUserNotFound, msgUserNotFound, err)
+}
+
+// クライアントに If-Match ヘッダーの条件を満たさないエラー（エラーコード PreconditionFailed）を返します。
+func preconditionFailed(c echo.Context, err error) error {
+	return newErrorResponse(c, ErrorCodePreconditionFailed, msgPreconditionFailed, err)
+}
+
+// ユーザーのステータスの変更に失敗したエラーを返します。
+// 遷移表で許可されていない変更の場合（domain.ErrInvalidStatusTransition）は InvalidStatusTransition エラーを、それ以外の場合はサーバーエラーを返します。
+func changeUserStatusFailed(c echo.Context, err error) error {
+	if errors.Is(err, domain.ErrInvalidStatusTransition) {
+		return newErrorResponse(c, ErrorCodeInvalidStatusTransition, msgInvalidStatusTransition, err)
+	}
+	return internalServerError(c, msgChangeUserStatusFailed, err)
+}
+
+// ユーザーの保存に失敗したエラーを返します。
+// 他の操作と競合した場合（domain.ErrConflict）は UserConflict エラーを、それ以外の場合はサーバーエラーを返します。
+func saveUserFailed(c echo.Context, err error) error {
+	if errors.Is(err, domain.ErrConflict) {
+		return newErrorResponse(c, ErrorCodeUserConflict, msgUserConflict, err)
+	}
+	return internalServerError(c, msgSaveUserFailed, err)
+}
+
+// クライアントにサーバーエラー（エラーコード InternalServerError）を返します。
+func internalServerError(c echo.Context, messageID string, err error) error {
+	return newErrorResponse(c, ErrorCodeInternalServerError, messageID, err)
+}
+
+// バリデーションエラーを、フィールド名の昇順のエラーの詳細に変換します。メッセージはリクエストの言語に変換します。
+// fieldNames は ozzo-validation のフィールド名からクライアントに返すフィールド名への対応表、prefix はネストしたフィールドの接頭辞です。
+func errorDetails(c echo.Context, fieldNames map[string]string, prefix string, errs validation.Errors) []ErrorDetail {
+	details := []ErrorDetail{}
+	for key, err := range errs {
+		field := key
+		if name, ok := fieldNames[key]; ok {
+			field = name
+		}
+		field = prefix + field
+
+		switch err := err.(type) {
+		case validation.Errors:
+			// ネストした構造体や、配列の要素のバリデーションエラー
+			details = append(details, errorDetails(c, nil, field+".", err)...)
+		case validation.Error:
+			details = append(details, ErrorDetail{Field: field, Code: err.Code(), Message: localize(c, err.Message())})
+		default:
+			// validation.By 等で返した、コードを持たないエラー
+			details = append(details, ErrorDetail{Field: field, Code: "validation_invalid", Message: localize(c, err.Error())})
+		}
+	}
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].Field < details[j].Field
+	})
+	return details
+}
+
+// リクエストの構造体のフィールドについて、ozzo-validation のエラーのフィールド名（json タグの名前あるいはフィールド名）から、
+// クライアントに返すフィールド名（json, param, query タグのいずれかの名前）への対応表を返します。
+func requestFieldNames(request interface{}) map[string]string {
+	t := reflect.TypeOf(request)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fieldNames := make(map[string]string, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Name
+		if name := tagName(field, validation.ErrorTag); name != "" {
+			key = name
+		}
+		for _, tag := range []string{"json", "param", "query"} {
+			if name := tagName(field, tag); name != "" {
+				fieldNames[key] = name
+				break
+			}
+		}
+	}
+	return fieldNames
 }
 
-// クライアントにサーバーエラー（HTTP ステータスコード 500, エラーコード InternalServerError）を返します。
-func internalServerError(c echo.Context, message string, err error) error {
-	return newErrorResponse(c, http.StatusInternalServerError, "InternalServerError", message, err)
+// 構造体のフィールドのタグから名前（カンマより前の部分）を返します。タグがないか "-" の場合は空文字列を返します。
+func tagName(field reflect.StructField, tag string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
 }
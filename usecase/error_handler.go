@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nekonoshiri/go-echo-sample/i18n"
+	"nekonoshiri/go-echo-sample/logging"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RFC 7807 のエラーレスポンス（Problem Details）の Content-Type。
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// RFC 7807 のエラーレスポンスの type の接頭辞。type はこれにエラーコードを付けた相対 URI です（例: /errors/UserNotFound）。
+const problemTypePrefix = "/errors/"
+
+// RFC 7807 のエラーレスポンス（Problem Details）。
+// クライアントが Accept ヘッダーで application/problem+json を指定した場合に、[ErrorResponse] の代わりに返します。
+type ProblemDetails struct {
+	// エラーの種類を表す URI。エラーコードから決まります。
+	Type string `json:"type"`
+	// エラーの種類を表す短い説明。
+	Title string `json:"title"`
+	// HTTP ステータスコード。
+	Status int `json:"status"`
+	// エラーの詳細。[ErrorResponse] の message と同じです。
+	Detail string `json:"detail"`
+	// エラーが発生したリクエストの URI。
+	Instance string `json:"instance"`
+	// エラーコード（拡張メンバー）。[ErrorResponse] の code と同じです。
+	Code string `json:"code"`
+	// リクエスト ID（拡張メンバー）。
+	RequestID string `json:"requestID,omitempty"`
+	// 内部のエラーの詳細（拡張メンバー）。開発モードの場合のみ設定されます。
+	Debug string `json:"debug,omitempty"`
+	// フィールド毎のバリデーションエラー（拡張メンバー）。[ErrorResponse] の details と同じです。
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// echo の HTTPErrorHandler を返します。すべてのエラーを [ErrorResponse] の形式の JSON でクライアントに返します。
+//
+// ユースケースが返したエラーはそのエラーコードとメッセージで、それ以外のエラー（存在しないルートへのリクエストや、
+// パニックなど）は HTTP ステータスコードに応じたエラーコードと定型のメッセージ（リクエストの言語）で返します。
+// エラーレスポンスにはリクエスト ID（X-Request-ID ヘッダーの値）を含めます。
+// クライアントが Accept ヘッダーで application/problem+json を指定した場合は、RFC 7807 の形式（[ProblemDetails]）で返します。
+//
+// ラップしているエラーはリクエストのロガーに出力します（サーバーエラーは error レベル、それ以外は info レベル）。
+// debug が true の場合（開発モード）のみ、ラップしているエラーの内容をエラーレスポンスの debug に含めます。
+// 本番環境では内部のエラーの内容がクライアントに漏れないよう、false にしてください。
+func NewHTTPErrorHandler(debug bool) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		httpStatusCode, response := toErrorResponse(c, err)
+		response.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+		if debug && response.Err != nil {
+			response.Debug = response.Err.Error()
+		}
+
+		ctx := c.Request().Context()
+		logger := logging.FromContext(ctx)
+		level := slog.LevelInfo
+		if httpStatusCode >= 500 {
+			level = slog.LevelError
+		}
+		logger.Log(ctx, level, response.Message,
+			"code", response.Code,
+			"status", httpStatusCode,
+			"error", response.Err,
+			"error_chain", errorChain(response.Err),
+		)
+
+		// Accept, Accept-Language ヘッダーによってレスポンスの形式や言語が変わることをキャッシュに伝える
+		c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+		c.Response().Header().Add(echo.HeaderVary, i18n.HeaderAcceptLanguage)
+		c.Response().Header().Set(i18n.HeaderContentLanguage, string(languageOf(c)))
+
+		var writeErr error
+		switch {
+		case c.Request().Method == http.MethodHead:
+			writeErr = c.NoContent(httpStatusCode)
+		case acceptsProblemJSON(c.Request().Header.Get(echo.HeaderAccept)):
+			c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+			writeErr = c.JSON(httpStatusCode, &ProblemDetails{
+				Type:      problemTypePrefix + response.Code,
+				Title:     errorTitle(c, response.Code),
+				Status:    httpStatusCode,
+				Detail:    response.Message,
+				Instance:  c.Request().RequestURI,
+				Code:      response.Code,
+				RequestID: response.RequestID,
+				Debug:     response.Debug,
+				Details:   response.Details,
+			})
+		default:
+			writeErr = c.JSON(httpStatusCode, response)
+		}
+		if writeErr != nil {
+			logger.Error("エラーレスポンスの書き込みに失敗しました", "error", writeErr)
+		}
+	}
+}
+
+// エラーを HTTP ステータスコードとエラーレスポンスに変換します。
+// 返すエラーレスポンスは新しく作成したもので、err が含むエラーレスポンスを変更しても影響しません。
+func toErrorResponse(c echo.Context, err error) (int, *ErrorResponse) {
+	var httpError *echo.HTTPError
+	if errors.As(err, &httpError) {
+		if errorResponse, ok := httpError.Message.(*ErrorResponse); ok {
+			response := *errorResponse
+			return httpError.Code, &response
+		}
+		errorCode := errorCodeOf(httpError.Code)
+		return httpError.Code, &ErrorResponse{
+			Code:    errorCode,
+			Message: errorTitle(c, errorCode),
+			Err:     err,
+		}
+	}
+
+	var errorResponse *ErrorResponse
+	if errors.As(err, &errorResponse) {
+		response := *errorResponse
+		return HTTPStatus(response.Code), &response
+	}
+
+	return http.StatusInternalServerError, &ErrorResponse{
+		Code:    ErrorCodeInternalServerError,
+		Message: errorTitle(c, ErrorCodeInternalServerError),
+		Err:     err,
+	}
+}
+
+// ユースケース以外で発生したエラーの HTTP ステータスコードに対応するエラーコードを返します。
+func errorCodeOf(httpStatusCode int) string {
+	switch {
+	case httpStatusCode == http.StatusNotFound:
+		return ErrorCodeNotFound
+	case httpStatusCode == http.StatusMethodNotAllowed:
+		return ErrorCodeMethodNotAllowed
+	case httpStatusCode == http.StatusTooManyRequests:
+		return ErrorCodeTooManyRequests
+	case httpStatusCode >= 500:
+		return ErrorCodeInternalServerError
+	default:
+		return ErrorCodeBadRequest
+	}
+}
+
+// ラップされているエラーを外側から順にたどり、それぞれのエラーメッセージを返します。
+func errorChain(err error) []string {
+	chain := []string{}
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, err.Error())
+	}
+	return chain
+}
+
+// Accept ヘッダーの値で、application/problem+json が受け入れ可能として指定されているかどうかを返します。
+// 品質値（q）が 0 の場合や、不正な場合は受け入れ可能ではないものとして扱います。
+func acceptsProblemJSON(accept string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil || mediaType != MIMEApplicationProblemJSON {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			quality, err := strconv.ParseFloat(q, 64)
+			if err != nil || quality <= 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/i18n"
+	"nekonoshiri/go-echo-sample/logging"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+// エラーハンドラーがすべてのエラーをエラーレスポンスの形式で返すことのテスト。
+func TestHTTPErrorHandler(t *testing.T) {
+	// ユースケースが返すエラー
+	userNotFoundErr := echo.NewHTTPError(http.StatusNotFound, &ErrorResponse{
+		Code:    ErrorCodeUserNotFound,
+		Message: "ユーザーが見つかりませんでした",
+		Err:     fmt.Errorf("U1: %w", domain.ErrUserNotFound),
+	})
+	// パニックから回復したエラー（内部のエラーの内容を含む）
+	panicErr := errors.New("secret internal error")
+
+	testCases := []struct {
+		name             string // テストケース名
+		err              error  // ハンドラーに渡すエラー
+		debug            bool   // 開発モードかどうか
+		wantStatusCode   int    // 期待される HTTP ステータスコード
+		wantResponseBody string // 期待されるレスポンスボディ
+		wantLog          string // ログに含まれるべき内部のエラーの内容
+	}{
+		{
+			name:           "ユースケースのエラー",
+			err:            userNotFoundErr,
+			wantStatusCode: http.StatusNotFound,
+			wantResponseBody: `{
+				"code": "UserNotFound",
+				"message": "ユーザーが見つかりませんでした",
+				"requestID": "R1"
+			}`,
+			wantLog: "ユーザーが見つかりません。",
+		},
+		{
+			name:           "ユースケースのエラー（開発モード）",
+			err:            userNotFoundErr,
+			debug:          true,
+			wantStatusCode: http.StatusNotFound,
+			wantResponseBody: `{
+				"code": "UserNotFound",
+				"message": "ユーザーが見つかりませんでした",
+				"requestID": "R1",
+				"debug": "U1: ユーザーが見つかりません。"
+			}`,
+			wantLog: "ユーザーが見つかりません。",
+		},
+		{
+			name:           "ルートが見つからない",
+			err:            echo.ErrNotFound,
+			wantStatusCode: http.StatusNotFound,
+			wantResponseBody: `{
+				"code": "NotFound",
+				"message": "リソースが見つかりませんでした",
+				"requestID": "R1"
+			}`,
+			wantLog: "Not Found",
+		},
+		{
+			name:           "メソッドが許可されていない",
+			err:            echo.ErrMethodNotAllowed,
+			wantStatusCode: http.StatusMethodNotAllowed,
+			wantResponseBody: `{
+				"code": "MethodNotAllowed",
+				"message": "メソッドが許可されていません",
+				"requestID": "R1"
+			}`,
+			wantLog: "Method Not Allowed",
+		},
+		{
+			name:           "パニック",
+			err:            panicErr,
+			wantStatusCode: http.StatusInternalServerError,
+			wantResponseBody: `{
+				"code": "InternalServerError",
+				"message": "サーバーエラーが発生しました",
+				"requestID": "R1"
+			}`,
+			wantLog: "secret internal error",
+		},
+		{
+			name:           "パニック（開発モード）",
+			err:            panicErr,
+			debug:          true,
+			wantStatusCode: http.StatusInternalServerError,
+			wantResponseBody: `{
+				"code": "InternalServerError",
+				"message": "サーバーエラーが発生しました",
+				"requestID": "R1",
+				"debug": "secret internal error"
+			}`,
+			wantLog: "secret internal error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodGet, "/users/U1", nil)
+			request = request.WithContext(logging.WithLogger(request.Context(), logging.New(&logs, "info")))
+			recorder := httptest.NewRecorder()
+			recorder.Header().Set(echo.HeaderXRequestID, "R1")
+			c := e.NewContext(request, recorder)
+
+			NewHTTPErrorHandler(tc.debug)(tc.err, c)
+
+			if recorder.Code != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, recorder.Code)
+			}
+			if diff := cmp.Diff(tc.wantResponseBody, recorder.Body.String(), UnmarshalJSON); diff != "" {
+				t.Errorf("期待されるレスポンスボディ (-) と実際のレスポンスボディ (+) が一致しませんでした:\n%s", diff)
+			}
+			// 本番モードでもログには内部のエラーの内容を出力する
+			if !strings.Contains(logs.String(), tc.wantLog) {
+				t.Errorf("ログにエラーの内容が出力されていません: %s", logs.String())
+			}
+		})
+	}
+}
+
+// HEAD リクエストの場合はレスポンスボディを返さないことのテスト。
+func TestHTTPErrorHandlerHead(t *testing.T) {
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodHead, "/users/U1", nil)
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+
+	NewHTTPErrorHandler(false)(echo.ErrNotFound, c)
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNotFound, recorder.Code)
+	}
+	if recorder.Body.Len() != 0 {
+		t.Errorf("レスポンスボディは空のはずですが、%q が返りました", recorder.Body.String())
+	}
+}
+
+// Accept ヘッダーで application/problem+json を指定した場合に、RFC 7807 の形式で返すことのテスト。
+func TestHTTPErrorHandlerProblemJSON(t *testing.T) {
+	err := echo.NewHTTPError(http.StatusNotFound, &ErrorResponse{
+		Code:    ErrorCodeUserNotFound,
+		Message: "ユーザーが見つかりませんでした",
+		Err:     domain.ErrUserNotFound,
+	})
+
+	testCases := []struct {
+		accept          string // Accept ヘッダー
+		wantContentType string // 期待される Content-Type
+	}{
+		{accept: "application/problem+json", wantContentType: MIMEApplicationProblemJSON},
+		{accept: "application/json;q=0.9, Application/Problem+JSON", wantContentType: MIMEApplicationProblemJSON},
+		{accept: "application/json", wantContentType: echo.MIMEApplicationJSONCharsetUTF8},
+		{accept: "application/json, application/problem+json;q=0", wantContentType: echo.MIMEApplicationJSONCharsetUTF8},
+		{accept: "application/problem+json; q=0.000", wantContentType: echo.MIMEApplicationJSONCharsetUTF8},
+		{accept: "application/problem+json;q=0.5", wantContentType: MIMEApplicationProblemJSON},
+		{accept: "", wantContentType: echo.MIMEApplicationJSONCharsetUTF8},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("accept=%s", tc.accept), func(t *testing.T) {
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodGet, "/users/U1?mustExist=true", nil)
+			request.Header.Set(echo.HeaderAccept, tc.accept)
+			recorder := httptest.NewRecorder()
+			recorder.Header().Set(echo.HeaderXRequestID, "R1")
+			c := e.NewContext(request, recorder)
+
+			NewHTTPErrorHandler(false)(err, c)
+
+			if recorder.Code != http.StatusNotFound {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNotFound, recorder.Code)
+			}
+			if contentType := recorder.Header().Get(echo.HeaderContentType); contentType != tc.wantContentType {
+				t.Errorf("期待される Content-Type は %q ですが、%q が返りました", tc.wantContentType, contentType)
+			}
+			if vary := recorder.Header().Get(echo.HeaderVary); vary != echo.HeaderAccept {
+				t.Errorf("期待される Vary は %q ですが、%q が返りました", echo.HeaderAccept, vary)
+			}
+
+			wantResponseBody := `{
+				"code": "UserNotFound",
+				"message": "ユーザーが見つかりませんでした",
+				"requestID": "R1"
+			}`
+			if tc.wantContentType == MIMEApplicationProblemJSON {
+				wantResponseBody = `{
+					"type": "/errors/UserNotFound",
+					"title": "ユーザーが見つかりませんでした",
+					"status": 404,
+					"detail": "ユーザーが見つかりませんでした",
+					"instance": "/users/U1?mustExist=true",
+					"code": "UserNotFound",
+					"requestID": "R1"
+				}`
+			}
+			if diff := cmp.Diff(wantResponseBody, recorder.Body.String(), UnmarshalJSON); diff != "" {
+				t.Errorf("期待されるレスポンスボディ (-) と実際のレスポンスボディ (+) が一致しませんでした:\n%s", diff)
+			}
+		})
+	}
+}
+
+// ユースケース以外で発生したエラーのメッセージが、Accept-Language ヘッダーに応じた言語になることのテスト。
+func TestHTTPErrorHandlerLocalized(t *testing.T) {
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/nope", nil)
+	request.Header.Set(i18n.HeaderAcceptLanguage, "en")
+	request.Header.Set(echo.HeaderAccept, MIMEApplicationProblemJSON)
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+
+	NewHTTPErrorHandler(false)(echo.ErrNotFound, c)
+
+	if contentLanguage := recorder.Header().Get(i18n.HeaderContentLanguage); contentLanguage != "en" {
+		t.Errorf("期待される Content-Language は %q ですが、%q が返りました", "en", contentLanguage)
+	}
+	wantResponseBody := `{
+		"type": "/errors/NotFound",
+		"title": "The resource was not found",
+		"status": 404,
+		"detail": "The resource was not found",
+		"instance": "/nope",
+		"code": "NotFound"
+	}`
+	if diff := cmp.Diff(wantResponseBody, recorder.Body.String(), UnmarshalJSON); diff != "" {
+		t.Errorf("期待されるレスポンスボディ (-) と実際のレスポンスボディ (+) が一致しませんでした:\n%s", diff)
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+// エラーコードから HTTP ステータスコードへの対応のテスト。
+func TestHTTPStatus(t *testing.T) {
+	testCases := []struct {
+		errorCode      string // エラーコード
+		wantStatusCode int    // 期待される HTTP ステータスコード
+	}{
+		{errorCode: ErrorCodeBadRequest, wantStatusCode: http.StatusBadRequest},
+		{errorCode: ErrorCodeUserNotFound, wantStatusCode: http.StatusNotFound},
+		{errorCode: ErrorCodeUserFrozen, wantStatusCode: http.StatusConflict},
+		{errorCode: ErrorCodeUserClosed, wantStatusCode: http.StatusConflict},
+		{errorCode: ErrorCodeInvalidStatusTransition, wantStatusCode: http.StatusConflict},
+		{errorCode: ErrorCodeInternalServerError, wantStatusCode: http.StatusInternalServerError},
+		{errorCode: "Unknown", wantStatusCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.errorCode, func(t *testing.T) {
+			if statusCode := HTTPStatus(tc.errorCode); statusCode != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, statusCode)
+			}
+		})
+	}
+}
+
+// リクエストのバリデーションエラーのフィールド毎のエラーのテスト。
+func TestInvalidRequest(t *testing.T) {
+	type testRequest struct {
+		UserID string `param:"userID"`
+		Limit  int    `query:"limit"`
+		Name   string `json:"name,omitempty"`
+		Tags   []string
+	}
+	request := testRequest{}
+	errs := validation.Errors{
+		"UserID": validation.ErrRequired.SetMessage("ユーザー ID は必須です"),
+		"Limit":  validation.ErrMinGreaterEqualThanRequired.SetMessage("最大取得件数は 1 以上です"),
+		"name":   errors.New("名前が不正です"),
+		"Tags": validation.Errors{
+			"0": validation.ErrRequired.SetMessage("タグは必須です"),
+		},
+	}
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	statusCode, errorResponse := ParseErrorResponse(t, invalidRequest(c, &request, errs))
+
+	if statusCode != http.StatusBadRequest {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusBadRequest, statusCode)
+	}
+	wantDetails := []ErrorDetail{
+		{Field: "Tags.0", Code: validation.ErrRequired.Code(), Message: "タグは必須です"},
+		{Field: "limit", Code: validation.ErrMinGreaterEqualThanRequired.Code(), Message: "最大取得件数は 1 以上です"},
+		{Field: "name", Code: "validation_invalid", Message: "名前が不正です"},
+		{Field: "userID", Code: validation.ErrRequired.Code(), Message: "ユーザー ID は必須です"},
+	}
+	if diff := cmp.Diff(wantDetails, errorResponse.Details); diff != "" {
+		t.Errorf("期待されるフィールド毎のエラー (-) と実際のフィールド毎のエラー (+) が一致しませんでした:\n%s", diff)
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+)
+
+// ETag ヘッダー（echo は定義していないため定義します）。
+const headerETag = "ETag"
+
+// 条件付き GET で、リソースがクライアントの持っているものから変更されていないことを表すエラー。
+// ユースケースの本体がこのエラーを返すと、[handle] はレスポンスボディを返さずに 304 (Not Modified) を返します。
+var errNotModified = errors.New("リソースは変更されていません")
+
+// ユーザーの ETag を返します。ETag はユーザーのバージョンから決まる強い ETag です（例: "3"）。
+func userETag(user *domain.User) string {
+	return `"` + strconv.FormatInt(user.Version, 10) + `"`
+}
+
+// If-Match ヘッダーの値 ifMatch が ETag etag に一致するかどうかを返します。
+// ifMatch が空の場合（ヘッダーが指定されていない場合）と * の場合は true を返します。
+// 比較は強い比較です。つまり、弱い ETag（W/ で始まる ETag）は一致しません。
+func matchesIfMatch(ifMatch string, etag string) bool {
+	if ifMatch == "" {
+		return true
+	}
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// If-None-Match ヘッダーの値 ifNoneMatch が ETag etag に一致するかどうかを返します。
+// ifNoneMatch が空の場合（ヘッダーが指定されていない場合）は false を、* の場合は true を返します。
+// 比較は弱い比較です。つまり、W/ の有無は無視します。
+func matchesIfNoneMatch(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// If-Modified-Since ヘッダーの値 ifModifiedSince の日時以降に、最終更新日時 lastModified から変更されていないかどうかを返します。
+// ifModifiedSince が空あるいは不正な日時の場合と、lastModified がゼロ値の場合は false を返します。
+// Last-Modified ヘッダーの精度に合わせて、秒単位で比較します。
+func notModifiedSince(ifModifiedSince string, lastModified time.Time) bool {
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
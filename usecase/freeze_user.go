@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+)
+
+// FreezeUser ユースケースのリクエスト。
+type FreezeUserRequest struct {
+	// ユーザー ID。必須で、1 文字以上 100 文字以下です。
+	UserID string `param:"userID"`
+	// 凍結を行う操作者の ID。必須で、1 文字以上 100 文字以下です。
+	OperatorID string `json:"operatorID"`
+	// 凍結の理由。必須で、1 文字以上 1000 文字以下です。
+	Reason string `json:"reason"`
+	// 凍結の期限。省略可能で、指定した場合は現在より後の日時でなければならず、その日時に凍結が自動的に解除されます。省略した場合は無期限に凍結します。
+	Until *time.Time `json:"until,omitempty"`
+	// If-Match ヘッダー。省略可能で、指定した場合はユーザーの ETag と一致しなければ PreconditionFailed エラーを返します。
+	IfMatch string `header:"If-Match" json:"-"`
+}
+
+func (request *FreezeUserRequest) validate() error {
+	return validateStruct(request, request.fieldRules())
+}
+
+func (request *FreezeUserRequest) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&request.UserID,
+			required(msgUserIDRequired),
+			runeLength(1, 100, msgUserIDLength),
+		),
+		field(&request.OperatorID,
+			required(msgOperatorIDRequired),
+			runeLength(1, 100, msgOperatorIDLength),
+		),
+		field(&request.Reason,
+			required(msgReasonRequired),
+			runeLength(1, 1000, msgReasonLength),
+		),
+	}
+}
+
+// FreezeUser ユースケースの仕様。
+var freezeUserSpec = &Spec{
+	Name:           "FreezeUser",
+	Method:         http.MethodPost,
+	Path:           "/users/:userID/freeze",
+	Summary:        "ユーザーを凍結状態にします",
+	Description:    "操作者の ID と理由は、ユーザーのステータス変更の記録として残ります。期限を指定すると、その日時に凍結が自動的に解除されます。ユーザーが既に凍結状態の場合は期限を変更します（期限が変わらない場合は何もしません）。本人確認待ちや退会済みのユーザーは凍結できません。",
+	Request:        &FreezeUserRequest{},
+	SuccessStatus:  http.StatusNoContent,
+	ErrorCodes:     []string{ErrorCodeBadRequest, ErrorCodeUserNotFound, ErrorCodeInvalidStatusTransition, ErrorCodeUserConflict, ErrorCodePreconditionFailed, ErrorCodeInternalServerError},
+	Permissions:    []Permission{PermissionModerateUsers},
+	RateLimitClass: RateLimitClassWrite,
+	BodyLog:        userBodyLog,
+}
+
+// FreezeUser ユースケース。ユーザーを凍結状態にします。
+//   - リクエスト: [FreezeUserRequest]
+//   - レスポンス: なし（HTTP ステータスコード 204）
+//
+// 成功した場合は、更新後のユーザーの ETag を ETag ヘッダーに設定します。
+// If-Match ヘッダーを指定すると、ユーザーがその ETag から更新されていない場合のみ実行します。
+//
+// 操作者の ID と理由は、ユーザーのステータス変更の記録として残ります。
+// 期限を指定すると、その日時に凍結が自動的に解除されます（省略した場合は無期限に凍結します）。
+// ユーザーが既に凍結状態の場合は期限を変更します（期限が変わらない場合は何もせず、ステータス変更の記録も更新しません）。
+// 本人確認待ちや退会済みのユーザーは凍結できません。
+//
+// このユースケースは、以下のエラーコードを返します。
+//   - BadRequest: リクエストが不正な場合。
+//   - UserNotFound: ユーザーが見つからなかった場合。
+//   - InvalidStatusTransition: ユーザーの現在のステータスからは凍結状態に変更できない場合。
+//   - UserConflict: ユーザーを取得してから保存するまでの間に、他の操作によってユーザーが更新された場合。
+//   - PreconditionFailed: If-Match ヘッダーがユーザーの ETag と一致しない場合。
+//   - InternalServerError: サーバーエラーが発生した場合。
+func FreezeUser(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error {
+	return handle(c, freezeUserSpec, func(c echo.Context, request *FreezeUserRequest) (*noContent, error) {
+		ctx := c.Request().Context()
+
+		user, err := userRepository.Get(ctx, request.UserID)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return nil, userNotFound(c, err)
+			}
+			return nil, internalServerError(c, msgGetUserFailed, err)
+		}
+
+		if !matchesIfMatch(request.IfMatch, userETag(user)) {
+			return nil, preconditionFailed(c, fmt.Errorf("If-Match %s がユーザーの ETag %s と一致しません", request.IfMatch, userETag(user)))
+		}
+
+		changed, err := user.Freeze(request.OperatorID, request.Reason, request.Until, clock)
+		if err != nil {
+			if errors.Is(err, domain.ErrFreezeUntilNotFuture) {
+				// 現在日時によって結果が変わるため、validate ではなくドメインで検証し、until のバリデーションエラーとして返す
+				return nil, invalidRequest(c, request, validation.Errors{"until": validation.NewError("validation_until_not_future", msgUntilFuture)})
+			}
+			return nil, changeUserStatusFailed(c, err)
+		}
+
+		// 何も変わらない場合は、バージョン（ETag）が変わらないように保存しない
+		if changed {
+			if err := userRepository.Put(ctx, user); err != nil {
+				return nil, saveUserFailed(c, err)
+			}
+		}
+
+		c.Response().Header().Set(headerETag, userETag(user))
+		return &noContent{}, nil
+	})
+}
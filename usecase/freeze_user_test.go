@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FreezeUser ユースケースの正常系のテスト。
+func TestFreezeUserOK(t *testing.T) {
+	var savedUser *domain.User
+	userRepository := &MockUserRepository{
+		get: func(ctx context.Context, userID string) (*domain.User, error) {
+			return &domain.User{
+				UserID:       userID,
+				Name:         "ユーザー１",
+				Status:       domain.UserStatusNormal,
+				RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			}, nil
+		},
+		put: func(ctx context.Context, user *domain.User) error {
+			savedUser = user
+			return nil
+		},
+	}
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodPost, "/users/:userID/freeze", strings.NewReader(`{"operatorID": "OP1", "reason": "凍結理由"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+	c.SetParamNames("userID")
+	c.SetParamValues("U1")
+
+	if err := FreezeUser(c, userRepository, domain.SystemClock); err != nil {
+		t.Fatalf("ユースケースがエラーを返しました: %v", err)
+	}
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNoContent, recorder.Code)
+	}
+	if savedUser == nil {
+		t.Fatalf("ユーザーが保存されませんでした")
+	}
+	if !savedUser.IsFrozen(domain.SystemClock) {
+		t.Errorf("凍結状態のユーザーが保存されるはずですが、ステータスが %q のユーザーが保存されました", savedUser.Status)
+	}
+	if change := savedUser.LastStatusChange; change == nil || change.OperatorID != "OP1" || change.Reason != "凍結理由" {
+		t.Errorf("操作者 ID %q, 理由 %q のステータス変更の記録が残るはずですが、%+v が保存されました", "OP1", "凍結理由", change)
+	}
+}
+
+// 期限を指定した FreezeUser ユースケースのテスト。
+func TestFreezeUserWithUntil(t *testing.T) {
+	var savedUser *domain.User
+	userRepository := &MockUserRepository{
+		get: func(ctx context.Context, userID string) (*domain.User, error) {
+			return &domain.User{
+				UserID:       userID,
+				Name:         "ユーザー１",
+				Status:       domain.UserStatusNormal,
+				RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			}, nil
+		},
+		put: func(ctx context.Context, user *domain.User) error {
+			savedUser = user
+			return nil
+		},
+	}
+
+	// 期限は、システムの時計ではなく、渡した時計の現在日時より後であればよい
+	clock := domain.NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	until := clock.Now().AddDate(0, 0, 7)
+	body := fmt.Sprintf(`{"operatorID": "OP1", "reason": "凍結理由", "until": %q}`, until.Format(time.RFC3339))
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodPost, "/users/:userID/freeze", strings.NewReader(body))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+	c.SetParamNames("userID")
+	c.SetParamValues("U1")
+
+	if err := FreezeUser(c, userRepository, clock); err != nil {
+		t.Fatalf("ユースケースがエラーを返しました: %v", err)
+	}
+	if savedUser == nil {
+		t.Fatalf("ユーザーが保存されませんでした")
+	}
+	if savedUser.FrozenUntil == nil || !savedUser.FrozenUntil.Equal(until) {
+		t.Errorf("凍結の期限が %v のユーザーが保存されるはずですが、%v が保存されました", until, savedUser.FrozenUntil)
+	}
+	if change := savedUser.LastStatusChange; change == nil || !change.ChangedAt.Equal(clock.Now()) {
+		t.Errorf("ステータスの変更日時が現在日時 %v のユーザーが保存されるはずですが、%+v が保存されました", clock.Now(), change)
+	}
+
+	// 期限を過ぎると凍結状態ではなくなる
+	if savedUser.IsFrozen(domain.NewFakeClock(until)) {
+		t.Errorf("期限を過ぎても凍結状態のままになっています")
+	}
+}
+
+// FreezeUser ユースケースのエラー系のテスト。
+func TestFreezeUserError(t *testing.T) {
+	// 期限の検証はドメインで行うため、通常状態のユーザーを返す
+	getNormalUser := func(ctx context.Context, userID string) (*domain.User, error) {
+		return &domain.User{
+			UserID:       userID,
+			Name:         "ユーザー１",
+			Status:       domain.UserStatusNormal,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		}, nil
+	}
+
+	testCases := []struct {
+		name           string                                                         // テストケース名
+		body           string                                                         // リクエストボディ
+		get            func(ctx context.Context, userID string) (*domain.User, error) // リポジトリの Get
+		wantStatusCode int                                                            // 期待される HTTP ステータスコード
+		wantErrorCode  string                                                         // 期待されるエラーコード
+	}{
+		{
+			name:           "操作者 ID が省略された場合",
+			body:           `{"reason": "凍結理由"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "BadRequest",
+		},
+		{
+			name:           "理由が省略された場合",
+			body:           `{"operatorID": "OP1"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "BadRequest",
+		},
+		{
+			name:           "期限が過去の日時の場合",
+			body:           `{"operatorID": "OP1", "reason": "凍結理由", "until": "1999-12-31T00:00:00Z"}`,
+			get:            getNormalUser,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "BadRequest",
+		},
+		{
+			name:           "期限が現在日時の場合",
+			body:           `{"operatorID": "OP1", "reason": "凍結理由", "until": "2000-01-01T00:00:00Z"}`,
+			get:            getNormalUser,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "BadRequest",
+		},
+		{
+			name: "ユーザーが見つからない場合",
+			body: `{"operatorID": "OP1", "reason": "凍結理由"}`,
+			get: func(ctx context.Context, userID string) (*domain.User, error) {
+				return nil, domain.ErrUserNotFound
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantErrorCode:  "UserNotFound",
+		},
+		{
+			name: "ユーザーが退会済みの場合",
+			body: `{"operatorID": "OP1", "reason": "凍結理由"}`,
+			get: func(ctx context.Context, userID string) (*domain.User, error) {
+				return &domain.User{
+					UserID:       userID,
+					Name:         "ユーザー１",
+					Status:       domain.UserStatusClosed,
+					RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				}, nil
+			},
+			wantStatusCode: http.StatusConflict,
+			wantErrorCode:  "InvalidStatusTransition",
+		},
+	}
+
+	// 現在日時は 2000-01-01T00:00:00Z とする
+	clock := domain.NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepository := &MockUserRepository{get: tc.get}
+
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodPost, "/users/:userID/freeze", strings.NewReader(tc.body))
+			request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c := e.NewContext(request, nil)
+			c.SetParamNames("userID")
+			c.SetParamValues("U1")
+
+			err := FreezeUser(c, userRepository, clock)
+			if err == nil {
+				t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+			}
+
+			statusCode, errorResponse := ParseErrorResponse(t, err)
+			if statusCode != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, statusCode)
+			}
+			if errorResponse.Code != tc.wantErrorCode {
+				t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", tc.wantErrorCode, errorResponse.Code)
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@ package usecase
 
 import (
 	"errors"
-	"fmt"
+	"net/http"
 	"time"
 
 	"nekonoshiri/go-echo-sample/domain"
@@ -15,81 +15,179 @@ import (
 type GetUserRequest struct {
 	// ユーザー ID。必須で、1 文字以上 100 文字以下です。
 	UserID string `param:"userID"`
+	// If-None-Match ヘッダー。省略可能で、指定した場合はユーザーの ETag と一致すれば 304 (Not Modified) を返します。
+	IfNoneMatch string `header:"If-None-Match" json:"-"`
+	// If-Modified-Since ヘッダー。省略可能で、指定した場合はその日時以降にユーザーが変更されていなければ 304 (Not Modified) を返します。
+	// If-None-Match ヘッダーを指定した場合は無視します。
+	IfModifiedSince string `header:"If-Modified-Since" json:"-"`
 }
 
 func (request *GetUserRequest) validate() error {
-	return validation.ValidateStruct(request,
-		validation.Field(&request.UserID,
-			validation.Required.Error("ユーザー ID は必須です"),
-			validation.RuneLength(1, 100).Error("ユーザー ID は 1 文字以上 100 文字以下です"),
+	return validateStruct(request, request.fieldRules())
+}
+
+func (request *GetUserRequest) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&request.UserID,
+			required(msgUserIDRequired),
+			runeLength(1, 100, msgUserIDLength),
 		),
-	)
+	}
 }
 
 // GetUser ユースケースのレスポンス。
 type GetUserResponse struct {
 	// 名前。必須で、1 文字以上 100 文字以下です。
 	Name string `json:"name"`
-	// ステータス。必須で、normal か frozen です。
+	// ステータス。必須で、[domain.UserStatuses] のいずれかです。
+	// 凍結や利用停止の期限が過ぎている場合は、自動的に解除される前でも normal です。
 	Status string `json:"status"`
 	// 登録日時。必須です。
 	RegisteredAt time.Time `json:"registeredAt"`
+	// 利用停止の期限。ステータスが suspended の場合のみ含まれ、それ以外の場合（期限が過ぎた場合を含む）は省略されます。
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+	// 凍結の期限。期限付きで凍結されている場合のみ含まれ、それ以外の場合（期限が過ぎた場合を含む）は省略されます。
+	FrozenUntil *time.Time `json:"frozenUntil,omitempty"`
+	// 最後のステータス変更の記録。ステータスが一度も変更されていない場合は省略されます。
+	LastStatusChange *GetUserResponseStatusChange `json:"lastStatusChange,omitempty"`
+}
+
+// GetUser ユースケースのレスポンスに含まれるステータス変更の記録。
+type GetUserResponseStatusChange struct {
+	// ステータスを変更した操作者の ID。必須で、1 文字以上 100 文字以下です。
+	OperatorID string `json:"operatorID"`
+	// ステータスを変更した理由。必須で、1 文字以上 1000 文字以下です。
+	Reason string `json:"reason"`
+	// ステータスの変更日時。必須です。
+	ChangedAt time.Time `json:"changedAt"`
 }
 
 func (response *GetUserResponse) validate() error {
-	return validation.ValidateStruct(response,
-		validation.Field(&response.Name,
-			validation.Required.Error("名前は必須です"),
-			validation.RuneLength(1, 100).Error("名前は 1 文字以上 100 文字以下です"),
+	return validateStruct(response, response.fieldRules())
+}
+
+func (response *GetUserResponse) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&response.Name,
+			required(msgNameRequired),
+			runeLength(1, 100, msgNameLength),
+		),
+		field(&response.Status,
+			required(msgStatusRequired),
+			in(msgStatusIn, userStatusValues()...),
+		),
+		field(&response.RegisteredAt,
+			required(msgRegisteredAtRequired),
+		),
+		field(&response.LastStatusChange,
+			validation.By(func(value interface{}) error {
+				if change := value.(*GetUserResponseStatusChange); change != nil {
+					return change.validate()
+				}
+				return nil
+			}),
+		),
+	}
+}
+
+func (response *GetUserResponseStatusChange) validate() error {
+	return validateStruct(response, response.fieldRules())
+}
+
+func (response *GetUserResponseStatusChange) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&response.OperatorID,
+			required(msgOperatorIDRequired),
+			runeLength(1, 100, msgOperatorIDLength),
 		),
-		validation.Field(&response.Status,
-			validation.Required.Error("ステータスは必須です"),
-			validation.In("normal", "frozen").Error("ステータスは normal か frozen です"),
+		field(&response.Reason,
+			required(msgReasonRequired),
+			runeLength(1, 1000, msgReasonLength),
 		),
-		validation.Field(&response.RegisteredAt,
-			validation.Required.Error("登録日時は必須です"),
+		field(&response.ChangedAt,
+			required(msgChangedAtRequired),
 		),
-	)
+	}
+}
+
+// GetUser ユースケースの仕様。
+var getUserSpec = &Spec{
+	Name:           "GetUser",
+	Method:         http.MethodGet,
+	Path:           "/users/:userID",
+	Summary:        "ユーザーを取得します",
+	Request:        &GetUserRequest{},
+	Response:       &GetUserResponse{},
+	SuccessStatus:  http.StatusOK,
+	ErrorCodes:     []string{ErrorCodeBadRequest, ErrorCodeUserNotFound, ErrorCodeInternalServerError},
+	Permissions:    []Permission{PermissionReadUsers},
+	RateLimitClass: RateLimitClassRead,
+	BodyLog:        userBodyLog,
 }
 
 // GetUser ユースケース。ユーザーを取得します。
 //   - リクエスト: [GetUserRequest]
 //   - レスポンス: [GetUserResponse]
 //
+// ETag ヘッダーにユーザーの ETag を、Last-Modified ヘッダーにユーザーの最終更新日時を設定します。
+// ETag は、ユーザーを更新するユースケースの If-Match ヘッダーに指定できます。
+// If-None-Match あるいは If-Modified-Since ヘッダーを指定した場合、ユーザーが変更されていなければ
+// レスポンスボディを返さずに HTTP ステータスコード 304 を返します。
+//
+// ステータスは clock の現在日時で判定し、凍結や利用停止の期限が過ぎている場合は、自動的に解除される前でも normal を返します。
+// この場合、保存されているユーザーは変わらない（ETag も変わらない）ものの、期限が過ぎる前とはレスポンスが異なるため、304 は返しません。
+//
 // このユースケースは、以下のエラーコードを返します。
 //   - BadRequest: リクエストが不正な場合。
 //   - UserNotFound: ユーザーが見つからなかった場合。
 //   - InternalServerError: サーバーエラーが発生した場合。
-func GetUser(c echo.Context, userRepository domain.UserRepository) error {
-	ctx := c.Request().Context()
-
-	var request GetUserRequest
-	if err := c.Bind(&request); err != nil {
-		return badRequest(c, "リクエストが不正です", err)
-	}
-	if err := request.validate(); err != nil {
-		if errs, ok := err.(validation.Errors); ok {
-			return badRequest(c, fmt.Sprintf("リクエストが不正です: %v", errs), err)
+func GetUser(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error {
+	return handle(c, getUserSpec, func(c echo.Context, request *GetUserRequest) (*GetUserResponse, error) {
+		user, err := userRepository.Get(c.Request().Context(), request.UserID)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return nil, userNotFound(c, err)
+			}
+			return nil, internalServerError(c, msgGetUserFailed, err)
 		}
-		return internalServerError(c, "リクエストのバリデーションに失敗しました", err)
-	}
 
-	user, err := userRepository.Get(ctx, request.UserID)
-	if err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
-			return newErrorResponse(c, 400, "UserNotFound", "ユーザーが見つかりませんでした", err)
+		status := user.StatusAt(clock)
+		etag := userETag(user)
+		c.Response().Header().Set(headerETag, etag)
+		if !user.UpdatedAt.IsZero() {
+			c.Response().Header().Set(echo.HeaderLastModified, user.UpdatedAt.UTC().Format(http.TimeFormat))
+		}
+		// 期限が過ぎて、保存されているステータスとレスポンスのステータスが異なる場合は、ETag が同じでもレスポンスが変わるので 304 を返さない
+		if status == user.Status {
+			// If-None-Match を指定した場合は If-Modified-Since を無視する（RFC 9110）
+			if request.IfNoneMatch != "" {
+				if matchesIfNoneMatch(request.IfNoneMatch, etag) {
+					return nil, errNotModified
+				}
+			} else if notModifiedSince(request.IfModifiedSince, user.UpdatedAt) {
+				return nil, errNotModified
+			}
 		}
-		return internalServerError(c, "ユーザーの取得に失敗しました", err)
-	}
-
-	response := GetUserResponse{
-		Name:         user.Name,
-		Status:       string(user.Status),
-		RegisteredAt: user.RegisteredAt,
-	}
-	if err := response.validate(); err != nil {
-		return internalServerError(c, "レスポンスのバリデーションに失敗しました", fmt.Errorf("%+v: %w", response, err))
-	}
 
-	return c.JSON(200, response)
+		response := &GetUserResponse{
+			Name:         user.Name,
+			Status:       string(status),
+			RegisteredAt: user.RegisteredAt,
+		}
+		// 期限が過ぎた場合は、期限も含めない
+		switch status {
+		case domain.UserStatusSuspended:
+			response.SuspendedUntil = user.SuspendedUntil
+		case domain.UserStatusFrozen:
+			response.FrozenUntil = user.FrozenUntil
+		}
+		if change := user.LastStatusChange; change != nil {
+			response.LastStatusChange = &GetUserResponseStatusChange{
+				OperatorID: change.OperatorID,
+				Reason:     change.Reason,
+				ChangedAt:  change.ChangedAt,
+			}
+		}
+		return response, nil
+	})
 }
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/logging"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/labstack/echo/v4"
@@ -17,6 +19,10 @@ import (
 
 // GetUser ユースケースの正常系のテスト。
 func TestGetUserOK(t *testing.T) {
+	clock := domain.NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	suspendedUntil := time.Date(4000, time.February, 1, 0, 0, 0, 0, time.UTC)
+	frozenUntil := time.Date(2000, time.January, 8, 0, 0, 0, 0, time.UTC)
+	expiredUntil := time.Date(1999, time.December, 31, 0, 0, 0, 0, time.UTC)
 	testCases := []struct {
 		userID           string      // 取得しようとするユーザーの ID
 		repositoryUser   domain.User // リポジトリが返すユーザー
@@ -43,6 +49,7 @@ func TestGetUserOK(t *testing.T) {
 				Name:         "ユーザー２",
 				Status:       domain.UserStatusFrozen,
 				RegisteredAt: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				Version:      3,
 			},
 			wantResponseBody: `{
 				"name": "ユーザー２",
@@ -50,6 +57,88 @@ func TestGetUserOK(t *testing.T) {
 				"registeredAt": "2000-01-01T00:00:00Z"
 			}`,
 		},
+		{
+			userID: "U3",
+			repositoryUser: domain.User{
+				UserID:       "U3",
+				Name:         "ユーザー３",
+				Status:       domain.UserStatusFrozen,
+				RegisteredAt: time.Date(3000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				LastStatusChange: &domain.UserStatusChange{
+					OperatorID: "OP1",
+					Reason:     "凍結理由",
+					ChangedAt:  time.Date(3000, time.January, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			wantResponseBody: `{
+				"name": "ユーザー３",
+				"status": "frozen",
+				"registeredAt": "3000-01-01T00:00:00Z",
+				"lastStatusChange": {
+					"operatorID": "OP1",
+					"reason": "凍結理由",
+					"changedAt": "3000-01-02T00:00:00Z"
+				}
+			}`,
+		},
+		{
+			userID: "U4",
+			repositoryUser: domain.User{
+				UserID:         "U4",
+				Name:           "ユーザー４",
+				Status:         domain.UserStatusSuspended,
+				RegisteredAt:   time.Date(4000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				SuspendedUntil: &suspendedUntil,
+				LastStatusChange: &domain.UserStatusChange{
+					OperatorID: "OP1",
+					Reason:     "利用停止理由",
+					ChangedAt:  time.Date(4000, time.January, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			wantResponseBody: `{
+				"name": "ユーザー４",
+				"status": "suspended",
+				"registeredAt": "4000-01-01T00:00:00Z",
+				"suspendedUntil": "4000-02-01T00:00:00Z",
+				"lastStatusChange": {
+					"operatorID": "OP1",
+					"reason": "利用停止理由",
+					"changedAt": "4000-01-02T00:00:00Z"
+				}
+			}`,
+		},
+		{
+			userID: "U5",
+			repositoryUser: domain.User{
+				UserID:       "U5",
+				Name:         "ユーザー５",
+				Status:       domain.UserStatusFrozen,
+				RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				FrozenUntil:  &frozenUntil,
+			},
+			wantResponseBody: `{
+				"name": "ユーザー５",
+				"status": "frozen",
+				"registeredAt": "1000-01-01T00:00:00Z",
+				"frozenUntil": "2000-01-08T00:00:00Z"
+			}`,
+		},
+		{
+			// 凍結の期限が過ぎている場合は、ジョブが解除する前でも normal で、期限も含まない
+			userID: "U6",
+			repositoryUser: domain.User{
+				UserID:       "U6",
+				Name:         "ユーザー６",
+				Status:       domain.UserStatusFrozen,
+				RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				FrozenUntil:  &expiredUntil,
+			},
+			wantResponseBody: `{
+				"name": "ユーザー６",
+				"status": "normal",
+				"registeredAt": "1000-01-01T00:00:00Z"
+			}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -70,7 +159,7 @@ func TestGetUserOK(t *testing.T) {
 			c.SetParamNames("userID")
 			c.SetParamValues(tc.userID)
 
-			if err := GetUser(c, userRepository); err != nil {
+			if err := GetUser(c, userRepository, clock); err != nil {
 				t.Fatalf("ユースケースがエラーを返しました: %v", err)
 			}
 			if recorder.Code != http.StatusOK {
@@ -79,6 +168,126 @@ func TestGetUserOK(t *testing.T) {
 			if diff := cmp.Diff(tc.wantResponseBody, recorder.Body.String(), UnmarshalJSON); diff != "" {
 				t.Errorf("期待されるリクエストボディ (-) と実際のリクエストボディ (+) が一致しませんでした:\n%s", diff)
 			}
+			if want, got := fmt.Sprintf(`"%d"`, tc.repositoryUser.Version), recorder.Header().Get("ETag"); got != want {
+				t.Errorf("期待される ETag ヘッダーは %s ですが、%s が返りました", want, got)
+			}
+		})
+	}
+}
+
+// GetUser ユースケースの条件付き GET のテスト。
+func TestGetUserConditional(t *testing.T) {
+	user := domain.User{
+		UserID:       "U1",
+		Name:         "ユーザー１",
+		Status:       domain.UserStatusNormal,
+		RegisteredAt: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Version:      3,
+		UpdatedAt:    time.Date(2000, time.January, 2, 12, 0, 0, 500, time.UTC),
+	}
+
+	testCases := []struct {
+		name           string            // テストケース名
+		header         map[string]string // リクエストヘッダー
+		wantStatusCode int               // 期待される HTTP ステータスコード
+	}{
+		{name: "条件なし", header: map[string]string{}, wantStatusCode: http.StatusOK},
+		{name: "If-None-Match が一致", header: map[string]string{"If-None-Match": `"3"`}, wantStatusCode: http.StatusNotModified},
+		{name: "If-None-Match が弱い ETag で一致", header: map[string]string{"If-None-Match": `"1", W/"3"`}, wantStatusCode: http.StatusNotModified},
+		{name: "If-None-Match が *", header: map[string]string{"If-None-Match": "*"}, wantStatusCode: http.StatusNotModified},
+		{name: "If-None-Match が不一致", header: map[string]string{"If-None-Match": `"2"`}, wantStatusCode: http.StatusOK},
+		{name: "If-Modified-Since が最終更新日時と同じ", header: map[string]string{"If-Modified-Since": "Sun, 02 Jan 2000 12:00:00 GMT"}, wantStatusCode: http.StatusNotModified},
+		{name: "If-Modified-Since が最終更新日時より後", header: map[string]string{"If-Modified-Since": "Mon, 03 Jan 2000 00:00:00 GMT"}, wantStatusCode: http.StatusNotModified},
+		{name: "If-Modified-Since が最終更新日時より前", header: map[string]string{"If-Modified-Since": "Sun, 02 Jan 2000 11:59:59 GMT"}, wantStatusCode: http.StatusOK},
+		{name: "If-Modified-Since が不正", header: map[string]string{"If-Modified-Since": "昨日"}, wantStatusCode: http.StatusOK},
+		{
+			name:           "If-None-Match を指定した場合は If-Modified-Since を無視",
+			header:         map[string]string{"If-None-Match": `"2"`, "If-Modified-Since": "Mon, 03 Jan 2000 00:00:00 GMT"},
+			wantStatusCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepository := &MockUserRepository{
+				get: func(ctx context.Context, userID string) (*domain.User, error) {
+					user := user
+					return &user, nil
+				},
+			}
+
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodGet, "/users/:userID", nil)
+			for key, value := range tc.header {
+				request.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			c := e.NewContext(request, recorder)
+			c.SetParamNames("userID")
+			c.SetParamValues("U1")
+
+			if err := GetUser(c, userRepository, domain.SystemClock); err != nil {
+				t.Fatalf("ユースケースがエラーを返しました: %v", err)
+			}
+			if recorder.Code != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, recorder.Code)
+			}
+			if tc.wantStatusCode == http.StatusNotModified && recorder.Body.Len() != 0 {
+				t.Errorf("304 の場合はレスポンスボディを返さないはずですが、%q が返りました", recorder.Body.String())
+			}
+			// 304 の場合も ETag と Last-Modified は返す
+			if etag := recorder.Header().Get("ETag"); etag != `"3"` {
+				t.Errorf("期待される ETag ヘッダーは %s ですが、%s が返りました", `"3"`, etag)
+			}
+			if lastModified := recorder.Header().Get("Last-Modified"); lastModified != "Sun, 02 Jan 2000 12:00:00 GMT" {
+				t.Errorf("期待される Last-Modified ヘッダーは %s ですが、%s が返りました", "Sun, 02 Jan 2000 12:00:00 GMT", lastModified)
+			}
+		})
+	}
+}
+
+// 凍結の期限が過ぎたユーザーは、ETag が一致しても 304 を返さないことのテスト。
+func TestGetUserConditionalExpiredFreeze(t *testing.T) {
+	clock := domain.NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	frozenUntil := clock.Now().Add(time.Hour)
+	userRepository := &MockUserRepository{
+		get: func(ctx context.Context, userID string) (*domain.User, error) {
+			return &domain.User{
+				UserID:       "U1",
+				Name:         "ユーザー１",
+				Status:       domain.UserStatusFrozen,
+				RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				Version:      3,
+				FrozenUntil:  &frozenUntil,
+			}, nil
+		},
+	}
+
+	for _, tc := range []struct {
+		name           string        // テストケース名
+		advance        time.Duration // 時計を進める時間
+		wantStatusCode int           // 期待される HTTP ステータスコード
+	}{
+		{name: "期限前", advance: 0, wantStatusCode: http.StatusNotModified},
+		{name: "期限後", advance: time.Hour, wantStatusCode: http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock.Advance(tc.advance)
+
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodGet, "/users/:userID", nil)
+			request.Header.Set("If-None-Match", `"3"`)
+			recorder := httptest.NewRecorder()
+			c := e.NewContext(request, recorder)
+			c.SetParamNames("userID")
+			c.SetParamValues("U1")
+
+			if err := GetUser(c, userRepository, clock); err != nil {
+				t.Fatalf("ユースケースがエラーを返しました: %v", err)
+			}
+			if recorder.Code != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, recorder.Code)
+			}
 		})
 	}
 }
@@ -102,7 +311,7 @@ func TestGetUserBadRequest(t *testing.T) {
 			c.SetParamNames("userID")
 			c.SetParamValues(tc.userID)
 
-			err := GetUser(c, userRepository)
+			err := GetUser(c, userRepository, domain.SystemClock)
 			if err == nil {
 				t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
 			}
@@ -132,16 +341,60 @@ func TestGetUserUserNotFound(t *testing.T) {
 	c.SetParamNames("userID")
 	c.SetParamValues("U1")
 
-	err := GetUser(c, userRepository)
+	err := GetUser(c, userRepository, domain.SystemClock)
 	if err == nil {
 		t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
 	}
 
 	statusCode, errorResponse := ParseErrorResponse(t, err)
-	if statusCode != http.StatusBadRequest {
-		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusBadRequest, statusCode)
+	if statusCode != http.StatusNotFound {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNotFound, statusCode)
 	}
 	if errorResponse.Code != "UserNotFound" {
 		t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", "UserNotFound", errorResponse.Code)
 	}
 }
+
+// GetUser ユースケースがレスポンスボディをログに出力する際に、名前とステータス変更の操作者の ID・理由を伏せることのテスト。
+func TestGetUserBodyLogRedactsPersonalData(t *testing.T) {
+	userRepository := &MockUserRepository{
+		get: func(ctx context.Context, userID string) (*domain.User, error) {
+			return &domain.User{
+				UserID:       "U1",
+				Name:         "ユーザー１",
+				Status:       domain.UserStatusFrozen,
+				RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				LastStatusChange: &domain.UserStatusChange{
+					OperatorID: "操作者１",
+					Reason:     "秘密の理由",
+					ChangedAt:  time.Date(1000, time.January, 2, 0, 0, 0, 0, time.UTC),
+				},
+			}, nil
+		},
+	}
+
+	var buf bytes.Buffer
+	logger := logging.New(&buf, "info")
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodGet, "/users/:userID", nil)
+	request = request.WithContext(logging.WithLogger(request.Context(), logger))
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+	c.SetParamNames("userID")
+	c.SetParamValues("U1")
+
+	if err := GetUser(c, userRepository, domain.SystemClock); err != nil {
+		t.Fatalf("ユースケースがエラーを返しました: %v", err)
+	}
+
+	logs := buf.String()
+	if !strings.Contains(logs, "レスポンスボディ") {
+		t.Errorf("レスポンスボディがログに出力されていません: %s", logs)
+	}
+	for _, secret := range []string{"ユーザー１", "操作者１", "秘密の理由"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("%q がログに出力されています: %s", secret, logs)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// ListUsers ユースケースで limit が省略された場合の最大取得件数。
+	listUsersDefaultLimit = 20
+	// ListUsers ユースケースで指定できる最大取得件数の上限。
+	listUsersMaxLimit = 100
+)
+
+// ListUsers ユースケースのリクエスト。
+type ListUsersRequest struct {
+	// 最大取得件数。省略可能で、指定する場合は 1 以上 100 以下です（0 も不正です）。省略した場合は 20 です。
+	Limit *int `query:"limit"`
+	// カーソル。省略可能で、続きを取得する場合は前回のレスポンスの nextCursor を指定します。
+	Cursor string `query:"cursor"`
+}
+
+func (request *ListUsersRequest) validate() error {
+	return validateStruct(request, request.fieldRules())
+}
+
+func (request *ListUsersRequest) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&request.Limit,
+			// minimum は 0 を検証しないため、明示的に指定された 0 はここで弾く
+			validation.NilOrNotEmpty.ErrorObject(validation.ErrMinGreaterEqualThanRequired.SetMessage(msgLimitRange)),
+			minimum(1, msgLimitRange),
+			maximum(listUsersMaxLimit, msgLimitRange),
+		),
+		field(&request.Cursor,
+			validation.By(func(value interface{}) error {
+				if _, err := decodeCursor(value.(string)); err != nil {
+					return validation.NewError("validation_invalid_cursor", msgCursorInvalid)
+				}
+				return nil
+			}),
+		),
+	}
+}
+
+// ListUsers ユースケースのレスポンス。
+type ListUsersResponse struct {
+	// ユーザー一覧。必須です（該当するユーザーがいない場合は空配列です）。
+	Users []ListUsersResponseUser `json:"users"`
+	// 次のページを取得するためのカーソル。続きがない場合は空文字列です。
+	NextCursor string `json:"nextCursor"`
+}
+
+// ListUsers ユースケースのレスポンスに含まれるユーザー。
+type ListUsersResponseUser struct {
+	// ユーザー ID。必須で、1 文字以上 100 文字以下です。
+	UserID string `json:"userID"`
+	// 名前。必須で、1 文字以上 100 文字以下です。
+	Name string `json:"name"`
+	// ステータス。必須で、[domain.UserStatuses] のいずれかです。
+	// 凍結や利用停止の期限が過ぎている場合は、自動的に解除される前でも normal です。
+	Status string `json:"status"`
+	// 登録日時。必須です。
+	RegisteredAt time.Time `json:"registeredAt"`
+}
+
+func (response *ListUsersResponse) validate() error {
+	return validateStruct(response, response.fieldRules())
+}
+
+func (response *ListUsersResponse) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&response.Users,
+			notNil(msgUsersRequired),
+			validation.Each(validation.By(func(value interface{}) error {
+				user := value.(ListUsersResponseUser)
+				return user.validate()
+			})),
+		),
+	}
+}
+
+func (response *ListUsersResponseUser) validate() error {
+	return validateStruct(response, response.fieldRules())
+}
+
+func (response *ListUsersResponseUser) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&response.UserID,
+			required(msgUserIDRequired),
+			runeLength(1, 100, msgUserIDLength),
+		),
+		field(&response.Name,
+			required(msgNameRequired),
+			runeLength(1, 100, msgNameLength),
+		),
+		field(&response.Status,
+			required(msgStatusRequired),
+			in(msgStatusIn, userStatusValues()...),
+		),
+		field(&response.RegisteredAt,
+			required(msgRegisteredAtRequired),
+		),
+	}
+}
+
+// ListUsers ユースケースの仕様。
+var listUsersSpec = &Spec{
+	Name:           "ListUsers",
+	Method:         http.MethodGet,
+	Path:           "/users",
+	Summary:        "ユーザー一覧を取得します",
+	Description:    "ユーザーはユーザー ID の昇順に返されます。レスポンスの nextCursor が空文字列でない場合、その値をリクエストの cursor に指定すると続きを取得できます。",
+	Request:        &ListUsersRequest{},
+	Response:       &ListUsersResponse{},
+	SuccessStatus:  http.StatusOK,
+	ErrorCodes:     []string{ErrorCodeBadRequest, ErrorCodeInternalServerError},
+	Permissions:    []Permission{PermissionReadUsers},
+	RateLimitClass: RateLimitClassRead,
+	BodyLog:        userBodyLog,
+}
+
+// ListUsers ユースケース。ユーザー一覧を取得します。
+//   - リクエスト: [ListUsersRequest]
+//   - レスポンス: [ListUsersResponse]
+//
+// ユーザーはユーザー ID の昇順に返されます。
+// レスポンスの nextCursor が空文字列でない場合、その値をリクエストの cursor に指定すると続きを取得できます。
+// ステータスは clock の現在日時で判定します（GetUser と同じく、期限が過ぎた凍結や利用停止は normal になります）。
+//
+// このユースケースは、以下のエラーコードを返します。
+//   - BadRequest: リクエストが不正な場合。
+//   - InternalServerError: サーバーエラーが発生した場合。
+func ListUsers(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error {
+	return handle(c, listUsersSpec, func(c echo.Context, request *ListUsersRequest) (*ListUsersResponse, error) {
+		limit := listUsersDefaultLimit
+		if request.Limit != nil {
+			limit = *request.Limit
+		}
+		// バリデーション済みなのでエラーは発生しない
+		exclusiveStartKey, _ := decodeCursor(request.Cursor)
+
+		users, lastEvaluatedKey, err := userRepository.List(c.Request().Context(), exclusiveStartKey, limit)
+		if err != nil {
+			return nil, internalServerError(c, msgListUsersFailed, err)
+		}
+
+		response := &ListUsersResponse{
+			Users:      make([]ListUsersResponseUser, 0, len(users)),
+			NextCursor: encodeCursor(lastEvaluatedKey),
+		}
+		for _, user := range users {
+			response.Users = append(response.Users, ListUsersResponseUser{
+				UserID:       user.UserID,
+				Name:         user.Name,
+				Status:       string(user.StatusAt(clock)),
+				RegisteredAt: user.RegisteredAt,
+			})
+		}
+		return response, nil
+	})
+}
+
+// リポジトリの lastEvaluatedKey をクライアントに返すカーソルに変換します。
+// クライアントがカーソルの中身に依存しないよう、不透明な文字列にエンコードします。
+// lastEvaluatedKey が空文字列の場合は空文字列を返します。
+func encodeCursor(lastEvaluatedKey string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(lastEvaluatedKey))
+}
+
+// クライアントから受け取ったカーソルをリポジトリの exclusiveStartKey に変換します。
+// cursor が空文字列の場合は空文字列を返します。
+func decodeCursor(cursor string) (string, error) {
+	exclusiveStartKey, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", err
+	}
+	return string(exclusiveStartKey), nil
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+// ListUsers ユースケースの正常系のテスト。
+func TestListUsersOK(t *testing.T) {
+	clock := domain.NewFakeClock(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	expiredUntil := time.Date(1999, time.December, 31, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name                  string        // テストケース名
+		query                 string        // クエリ文字列
+		wantExclusiveStartKey string        // リポジトリに渡されるべき exclusiveStartKey
+		wantLimit             int           // リポジトリに渡されるべき limit
+		repositoryUsers       []domain.User // リポジトリが返すユーザー一覧
+		repositoryLastKey     string        // リポジトリが返す lastEvaluatedKey
+		wantResponseBody      string        // 期待されるレスポンスボディ
+	}{
+		{
+			name:                  "パラメータを省略した場合",
+			query:                 "",
+			wantExclusiveStartKey: "",
+			wantLimit:             listUsersDefaultLimit,
+			repositoryUsers: []domain.User{
+				{
+					UserID:       "U1",
+					Name:         "ユーザー１",
+					Status:       domain.UserStatusNormal,
+					RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					UserID:       "U2",
+					Name:         "ユーザー２",
+					Status:       domain.UserStatusFrozen,
+					RegisteredAt: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			repositoryLastKey: "U2",
+			wantResponseBody: `{
+				"users": [
+					{"userID": "U1", "name": "ユーザー１", "status": "normal", "registeredAt": "1000-01-01T00:00:00Z"},
+					{"userID": "U2", "name": "ユーザー２", "status": "frozen", "registeredAt": "2000-01-01T00:00:00Z"}
+				],
+				"nextCursor": "VTI"
+			}`,
+		},
+		{
+			name:                  "カーソルと最大取得件数を指定した場合",
+			query:                 "?limit=2&cursor=VTI",
+			wantExclusiveStartKey: "U2",
+			wantLimit:             2,
+			repositoryUsers: []domain.User{
+				{
+					UserID:       "U3",
+					Name:         "ユーザー３",
+					Status:       domain.UserStatusNormal,
+					RegisteredAt: time.Date(3000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					// 凍結の期限が過ぎている場合は、ジョブが解除する前でも normal
+					UserID:       "U4",
+					Name:         "ユーザー４",
+					Status:       domain.UserStatusFrozen,
+					RegisteredAt: time.Date(4000, time.January, 1, 0, 0, 0, 0, time.UTC),
+					FrozenUntil:  &expiredUntil,
+				},
+			},
+			repositoryLastKey: "",
+			wantResponseBody: `{
+				"users": [
+					{"userID": "U3", "name": "ユーザー３", "status": "normal", "registeredAt": "3000-01-01T00:00:00Z"},
+					{"userID": "U4", "name": "ユーザー４", "status": "normal", "registeredAt": "4000-01-01T00:00:00Z"}
+				],
+				"nextCursor": ""
+			}`,
+		},
+		{
+			name:                  "ユーザーが１人もいない場合",
+			query:                 "",
+			wantExclusiveStartKey: "",
+			wantLimit:             listUsersDefaultLimit,
+			repositoryUsers:       []domain.User{},
+			repositoryLastKey:     "",
+			wantResponseBody:      `{"users": [], "nextCursor": ""}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepository := &MockUserRepository{
+				list: func(ctx context.Context, exclusiveStartKey string, limit int) ([]domain.User, string, error) {
+					if exclusiveStartKey != tc.wantExclusiveStartKey {
+						t.Errorf("exclusiveStartKey に %q ではなく %q が指定されました", tc.wantExclusiveStartKey, exclusiveStartKey)
+					}
+					if limit != tc.wantLimit {
+						t.Errorf("limit に %d ではなく %d が指定されました", tc.wantLimit, limit)
+					}
+					return tc.repositoryUsers, tc.repositoryLastKey, nil
+				},
+			}
+
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodGet, "/users"+tc.query, nil)
+			recorder := httptest.NewRecorder()
+			c := e.NewContext(request, recorder)
+
+			if err := ListUsers(c, userRepository, clock); err != nil {
+				t.Fatalf("ユースケースがエラーを返しました: %v", err)
+			}
+			if recorder.Code != http.StatusOK {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusOK, recorder.Code)
+			}
+			if diff := cmp.Diff(tc.wantResponseBody, recorder.Body.String(), UnmarshalJSON); diff != "" {
+				t.Errorf("期待されるレスポンスボディ (-) と実際のレスポンスボディ (+) が一致しませんでした:\n%s", diff)
+			}
+		})
+	}
+}
+
+// ListUsers ユースケースのリクエストのバリデーションのテスト。
+func TestListUsersBadRequest(t *testing.T) {
+	userRepository := &MockUserRepository{}
+
+	testCases := []struct {
+		query       string        // クエリ文字列
+		wantDetails []ErrorDetail // 期待されるフィールド毎のエラー
+	}{
+		{
+			query: "?limit=0",
+			wantDetails: []ErrorDetail{
+				{Field: "limit", Code: validation.ErrMinGreaterEqualThanRequired.Code(), Message: "最大取得件数は 1 以上 100 以下です"},
+			},
+		},
+		{
+			query: "?limit=-1",
+			wantDetails: []ErrorDetail{
+				{Field: "limit", Code: validation.ErrMinGreaterEqualThanRequired.Code(), Message: "最大取得件数は 1 以上 100 以下です"},
+			},
+		},
+		{
+			query: "?limit=101",
+			wantDetails: []ErrorDetail{
+				{Field: "limit", Code: validation.ErrMaxLessEqualThanRequired.Code(), Message: "最大取得件数は 1 以上 100 以下です"},
+			},
+		},
+		{
+			// バインドに失敗する場合は、フィールド毎のエラーは返らない
+			query: "?limit=abc",
+		},
+		{
+			query: "?limit=101&cursor=!!!",
+			wantDetails: []ErrorDetail{
+				{Field: "cursor", Code: "validation_invalid_cursor", Message: "カーソルが不正です"},
+				{Field: "limit", Code: validation.ErrMaxLessEqualThanRequired.Code(), Message: "最大取得件数は 1 以上 100 以下です"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodGet, "/users"+tc.query, nil)
+			c := e.NewContext(request, nil)
+
+			err := ListUsers(c, userRepository, domain.SystemClock)
+			if err == nil {
+				t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+			}
+
+			statusCode, errorResponse := ParseErrorResponse(t, err)
+			if statusCode != http.StatusBadRequest {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusBadRequest, statusCode)
+			}
+			if errorResponse.Code != "BadRequest" {
+				t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", "BadRequest", errorResponse.Code)
+			}
+			if diff := cmp.Diff(tc.wantDetails, errorResponse.Details); diff != "" {
+				t.Errorf("期待されるフィールド毎のエラー (-) と実際のフィールド毎のエラー (+) が一致しませんでした:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"nekonoshiri/go-echo-sample/i18n"
+
+	"github.com/labstack/echo/v4"
+)
+
+// メッセージ ID。エラーレスポンスのメッセージと、バリデーションのエラーメッセージに使用します。
+// メッセージ ID を追加する場合は、messages にすべての言語のメッセージも追加してください。
+const (
+	// エラーレスポンスのメッセージ。
+	msgBadRequest               = "error.bad_request"
+	msgBadRequestWithErrors     = "error.bad_request_with_errors"
+	msgUserNotFound             = "error.user_not_found"
+	msgUserFrozen               = "error.user_frozen"
+	msgUserFrozenNameChange     = "error.user_frozen_name_change"
+	msgUserClosed               = "error.user_closed"
+	msgUserClosedNameChange     = "error.user_closed_name_change"
+	msgInvalidStatusTransition  = "error.invalid_status_transition"
+	msgUserConflict             = "error.user_conflict"
+	msgPreconditionFailed       = "error.precondition_failed"
+	msgNotFound                 = "error.not_found"
+	msgMethodNotAllowed         = "error.method_not_allowed"
+	msgTooManyRequests          = "error.too_many_requests"
+	msgInternalServerError      = "error.internal_server_error"
+	msgRequestValidationFailed  = "error.request_validation_failed"
+	msgResponseValidationFailed = "error.response_validation_failed"
+	msgGetUserFailed            = "error.get_user_failed"
+	msgListUsersFailed          = "error.list_users_failed"
+	msgSaveUserFailed           = "error.save_user_failed"
+	msgDeleteUserFailed         = "error.delete_user_failed"
+	msgChangeUserNameFailed     = "error.change_user_name_failed"
+	msgChangeUserStatusFailed   = "error.change_user_status_failed"
+
+	// バリデーションのエラーメッセージ。
+	msgUserIDRequired       = "validation.user_id.required"
+	msgUserIDLength         = "validation.user_id.length"
+	msgNameRequired         = "validation.name.required"
+	msgNameLength           = "validation.name.length"
+	msgOperatorIDRequired   = "validation.operator_id.required"
+	msgOperatorIDLength     = "validation.operator_id.length"
+	msgReasonRequired       = "validation.reason.required"
+	msgReasonLength         = "validation.reason.length"
+	msgStatusRequired       = "validation.status.required"
+	msgStatusIn             = "validation.status.in"
+	msgRegisteredAtRequired = "validation.registered_at.required"
+	msgChangedAtRequired    = "validation.changed_at.required"
+	msgUsersRequired        = "validation.users.required"
+	msgLimitRange           = "validation.limit.range"
+	msgCursorInvalid        = "validation.cursor.invalid"
+	msgUntilFuture          = "validation.until.future"
+)
+
+// メッセージカタログ。
+var messages = i18n.Catalog{
+	msgBadRequest:               {i18n.Japanese: "リクエストが不正です", i18n.English: "The request is invalid"},
+	msgBadRequestWithErrors:     {i18n.Japanese: "リクエストが不正です: %s", i18n.English: "The request is invalid: %s"},
+	msgUserNotFound:             {i18n.Japanese: "ユーザーが見つかりませんでした", i18n.English: "The user was not found"},
+	msgUserFrozen:               {i18n.Japanese: "ユーザーが凍結状態です", i18n.English: "The user is frozen"},
+	msgUserFrozenNameChange:     {i18n.Japanese: "凍結状態のユーザーの名前は変更できません", i18n.English: "The name of a frozen user cannot be changed"},
+	msgUserClosed:               {i18n.Japanese: "ユーザーが退会済みです", i18n.English: "The user is closed"},
+	msgUserClosedNameChange:     {i18n.Japanese: "退会済みのユーザーの名前は変更できません", i18n.English: "The name of a closed user cannot be changed"},
+	msgInvalidStatusTransition:  {i18n.Japanese: "ユーザーの現在のステータスからは、このステータスに変更できません", i18n.English: "The user's status cannot be changed to this status from the current status"},
+	msgUserConflict:             {i18n.Japanese: "ユーザーが他の操作によって更新されました。取得し直してから再試行してください", i18n.English: "The user was updated by another operation. Please get it again and retry"},
+	msgPreconditionFailed:       {i18n.Japanese: "ユーザーが更新されています（If-Match が一致しません）", i18n.English: "The user has been modified (If-Match does not match)"},
+	msgNotFound:                 {i18n.Japanese: "リソースが見つかりませんでした", i18n.English: "The resource was not found"},
+	msgMethodNotAllowed:         {i18n.Japanese: "メソッドが許可されていません", i18n.English: "The method is not allowed"},
+	msgTooManyRequests:          {i18n.Japanese: "リクエストが多すぎます。しばらく待ってから再試行してください", i18n.English: "Too many requests. Please retry later"},
+	msgInternalServerError:      {i18n.Japanese: "サーバーエラーが発生しました", i18n.English: "An internal server error occurred"},
+	msgRequestValidationFailed:  {i18n.Japanese: "リクエストのバリデーションに失敗しました", i18n.English: "Failed to validate the request"},
+	msgResponseValidationFailed: {i18n.Japanese: "レスポンスのバリデーションに失敗しました", i18n.English: "Failed to validate the response"},
+	msgGetUserFailed:            {i18n.Japanese: "ユーザーの取得に失敗しました", i18n.English: "Failed to get the user"},
+	msgListUsersFailed:          {i18n.Japanese: "ユーザー一覧の取得に失敗しました", i18n.English: "Failed to list users"},
+	msgSaveUserFailed:           {i18n.Japanese: "ユーザーの保存に失敗しました", i18n.English: "Failed to save the user"},
+	msgDeleteUserFailed:         {i18n.Japanese: "ユーザーの削除に失敗しました", i18n.English: "Failed to delete the user"},
+	msgChangeUserNameFailed:     {i18n.Japanese: "ユーザーの名前の変更に失敗しました", i18n.English: "Failed to change the user's name"},
+	msgChangeUserStatusFailed:   {i18n.Japanese: "ユーザーのステータスの変更に失敗しました", i18n.English: "Failed to change the user's status"},
+
+	msgUserIDRequired:       {i18n.Japanese: "ユーザー ID は必須です", i18n.English: "User ID is required"},
+	msgUserIDLength:         {i18n.Japanese: "ユーザー ID は 1 文字以上 100 文字以下です", i18n.English: "User ID must be between 1 and 100 characters"},
+	msgNameRequired:         {i18n.Japanese: "名前は必須です", i18n.English: "Name is required"},
+	msgNameLength:           {i18n.Japanese: "名前は 1 文字以上 100 文字以下です", i18n.English: "Name must be between 1 and 100 characters"},
+	msgOperatorIDRequired:   {i18n.Japanese: "操作者 ID は必須です", i18n.English: "Operator ID is required"},
+	msgOperatorIDLength:     {i18n.Japanese: "操作者 ID は 1 文字以上 100 文字以下です", i18n.English: "Operator ID must be between 1 and 100 characters"},
+	msgReasonRequired:       {i18n.Japanese: "理由は必須です", i18n.English: "Reason is required"},
+	msgReasonLength:         {i18n.Japanese: "理由は 1 文字以上 1000 文字以下です", i18n.English: "Reason must be between 1 and 1000 characters"},
+	msgStatusRequired:       {i18n.Japanese: "ステータスは必須です", i18n.English: "Status is required"},
+	msgStatusIn:             {i18n.Japanese: "ステータスが不正です", i18n.English: "Status is invalid"},
+	msgRegisteredAtRequired: {i18n.Japanese: "登録日時は必須です", i18n.English: "Registration time is required"},
+	msgChangedAtRequired:    {i18n.Japanese: "変更日時は必須です", i18n.English: "Change time is required"},
+	msgUsersRequired:        {i18n.Japanese: "ユーザー一覧は必須です", i18n.English: "Users are required"},
+	msgLimitRange:           {i18n.Japanese: "最大取得件数は 1 以上 100 以下です", i18n.English: "Limit must be between 1 and 100"},
+	msgCursorInvalid:        {i18n.Japanese: "カーソルが不正です", i18n.English: "The cursor is invalid"},
+	msgUntilFuture:          {i18n.Japanese: "期限は現在より後の日時です", i18n.English: "Until must be in the future"},
+}
+
+// リクエストの Accept-Language ヘッダーから、メッセージの言語を返します。
+func languageOf(c echo.Context) i18n.Language {
+	return i18n.ParseAcceptLanguage(c.Request().Header.Get(i18n.HeaderAcceptLanguage))
+}
+
+// メッセージ ID に対応する、リクエストの言語のメッセージを返します。
+func localize(c echo.Context, id string, args ...interface{}) string {
+	return messages.Message(languageOf(c), id, args...)
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/i18n"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+// メッセージカタログのすべてのメッセージに、すべての言語の翻訳があることのテスト。
+func TestMessagesHaveAllLanguages(t *testing.T) {
+	for id, translations := range messages {
+		for _, language := range i18n.SupportedLanguages {
+			message, ok := translations[language]
+			if !ok || message == "" {
+				t.Errorf("メッセージ %s に言語 %s の翻訳がありません", id, language)
+				continue
+			}
+			// 埋め込む引数の数が言語によって異なると、一部の言語でメッセージが壊れる
+			if strings.Count(message, "%") != strings.Count(translations[i18n.DefaultLanguage], "%") {
+				t.Errorf("メッセージ %s の言語 %s の翻訳の書式が、%s のものと一致しません", id, language, i18n.DefaultLanguage)
+			}
+		}
+	}
+}
+
+// Accept-Language ヘッダーに応じて、エラーレスポンスのメッセージの言語が変わることのテスト。
+func TestLocalizedErrorResponse(t *testing.T) {
+	testCases := []struct {
+		acceptLanguage string        // Accept-Language ヘッダー
+		wantMessage    string        // 期待されるメッセージ
+		wantDetails    []ErrorDetail // 期待されるフィールド毎のエラー
+	}{
+		{
+			acceptLanguage: "",
+			wantMessage:    "リクエストが不正です: userID: ユーザー ID は必須です",
+			wantDetails: []ErrorDetail{
+				{Field: "userID", Code: "validation_required", Message: "ユーザー ID は必須です"},
+			},
+		},
+		{
+			acceptLanguage: "en-US,en;q=0.9,ja;q=0.8",
+			wantMessage:    "The request is invalid: userID: User ID is required",
+			wantDetails: []ErrorDetail{
+				{Field: "userID", Code: "validation_required", Message: "User ID is required"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.acceptLanguage, func(t *testing.T) {
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodGet, "/users/:userID", nil)
+			request.Header.Set(i18n.HeaderAcceptLanguage, tc.acceptLanguage)
+			c := e.NewContext(request, nil)
+			c.SetParamNames("userID")
+			c.SetParamValues("")
+
+			err := GetUser(c, &MockUserRepository{}, domain.SystemClock)
+			if err == nil {
+				t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+			}
+
+			_, errorResponse := ParseErrorResponse(t, err)
+			if errorResponse.Message != tc.wantMessage {
+				t.Errorf("期待されるメッセージは %q ですが、%q が返りました", tc.wantMessage, errorResponse.Message)
+			}
+			if diff := cmp.Diff(tc.wantDetails, errorResponse.Details); diff != "" {
+				t.Errorf("期待されるフィールド毎のエラー (-) と実際のフィールド毎のエラー (+) が一致しませんでした:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"nekonoshiri/go-echo-sample/openapi"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// OpenAPI ドキュメントのタイトル。
+	openAPITitle = "go-echo-sample"
+	// OpenAPI ドキュメントに記載する API のバージョン。
+	openAPIVersion = "1.0.0"
+)
+
+// ルートの一覧から OpenAPI ドキュメントを生成します。
+//
+// リクエスト・レスポンスの構造体のタグからパラメーターやボディのスキーマを、fieldRules のルールが宣言した制約からスキーマの制約を、
+// エラーコードからエラーレスポンスを生成します。
+// 権限とレート制限の種類は、拡張フィールド x-permissions, x-rate-limit-class に出力します。
+// 制約に変換されるのは、rule.go の関数（required, runeLength, in 等）で作成したルールのみです。
+// ozzo-validation のルール（validation.By 等）を直接使った場合は無視します。
+func OpenAPIDocument(routes []*Route) *openapi.Document {
+	document := &openapi.Document{
+		OpenAPI: openapi.Version,
+		Info: openapi.Info{
+			Title:   openAPITitle,
+			Version: openAPIVersion,
+		},
+		Paths: map[string]*openapi.PathItem{},
+		Components: &openapi.Components{
+			Schemas: map[string]*openapi.Schema{
+				"ErrorResponse":  schemaOf(reflect.TypeOf(ErrorResponse{}), true),
+				"ProblemDetails": schemaOf(reflect.TypeOf(ProblemDetails{}), true),
+			},
+		},
+	}
+	for _, route := range routes {
+		path := openapi.Path(route.Path)
+		item, ok := document.Paths[path]
+		if !ok {
+			item = &openapi.PathItem{}
+			document.Paths[path] = item
+		}
+		if !item.SetOperation(route.Method, operationOf(route.Spec)) {
+			panic("OpenAPI ドキュメントが対応していない HTTP メソッドです: " + route.Method)
+		}
+	}
+	return document
+}
+
+// ユースケースの仕様から OpenAPI の操作を生成します。
+func operationOf(spec *Spec) *openapi.Operation {
+	operation := &openapi.Operation{
+		OperationID:    spec.Name,
+		Summary:        spec.Summary,
+		Description:    spec.Description,
+		Responses:      map[string]*openapi.Response{},
+		RateLimitClass: string(spec.RateLimitClass),
+	}
+	for _, permission := range spec.Permissions {
+		operation.Permissions = append(operation.Permissions, string(permission))
+	}
+
+	requestType := reflect.TypeOf(spec.Request).Elem()
+	rules := fieldRulesOf(requestType)
+	body := &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{}}
+	for i := 0; i < requestType.NumField(); i++ {
+		field := requestType.Field(i)
+		schema := schemaOf(field.Type, false)
+		required := applyRules(schema, rules[field.Name])
+		if name := tagName(field, "param"); name != "" {
+			operation.Parameters = append(operation.Parameters, &openapi.Parameter{Name: name, In: "path", Required: true, Schema: schema})
+		} else if name := tagName(field, "query"); name != "" {
+			operation.Parameters = append(operation.Parameters, &openapi.Parameter{Name: name, In: "query", Required: required, Schema: schema})
+		} else if name := tagName(field, "header"); name != "" {
+			operation.Parameters = append(operation.Parameters, &openapi.Parameter{Name: name, In: "header", Required: required, Schema: schema})
+		} else if name := tagName(field, "json"); name != "" {
+			body.Properties[name] = schema
+			if required {
+				body.Required = append(body.Required, name)
+			}
+		}
+	}
+	if len(body.Properties) > 0 {
+		operation.RequestBody = &openapi.RequestBody{
+			Required: true,
+			Content:  map[string]*openapi.MediaType{echo.MIMEApplicationJSON: {Schema: body}},
+		}
+	}
+
+	success := &openapi.Response{Description: http.StatusText(spec.SuccessStatus)}
+	if spec.Response != nil {
+		success.Content = map[string]*openapi.MediaType{
+			echo.MIMEApplicationJSON: {Schema: schemaOf(reflect.TypeOf(spec.Response).Elem(), true)},
+		}
+	}
+	operation.Responses[strconv.Itoa(spec.SuccessStatus)] = success
+	// If-None-Match ヘッダーを受け付ける（条件付き GET に対応する）場合は 304 を返しうる
+	for _, parameter := range operation.Parameters {
+		if parameter.In == "header" && strings.EqualFold(parameter.Name, "If-None-Match") {
+			operation.Responses[strconv.Itoa(http.StatusNotModified)] = &openapi.Response{Description: http.StatusText(http.StatusNotModified)}
+		}
+	}
+
+	// 同じ HTTP ステータスコードのエラーコードは 1 つのレスポンスにまとめる
+	// レート制限を行うルートは TooManyRequests エラーも返す
+	errorCodes := append([]string{}, spec.ErrorCodes...)
+	if spec.RateLimitClass != "" {
+		errorCodes = append(errorCodes, ErrorCodeTooManyRequests)
+	}
+	codesByStatus := map[int][]string{}
+	for _, code := range errorCodes {
+		status := HTTPStatus(code)
+		codesByStatus[status] = append(codesByStatus[status], code)
+	}
+	for status, codes := range codesByStatus {
+		operation.Responses[strconv.Itoa(status)] = &openapi.Response{
+			Description: "エラーコード: " + strings.Join(codes, ", "),
+			Content: map[string]*openapi.MediaType{
+				echo.MIMEApplicationJSON:   {Schema: openapi.Ref("ErrorResponse")},
+				MIMEApplicationProblemJSON: {Schema: openapi.Ref("ProblemDetails")},
+			},
+		}
+	}
+
+	return operation
+}
+
+// Go の型から OpenAPI のスキーマを生成します。
+// output が true の場合（レスポンスの場合）、omitempty でないフィールドは常に出力されるため必須とします。
+func schemaOf(t reflect.Type, output bool) *openapi.Schema {
+	if t == reflect.TypeOf(time.Time{}) {
+		return &openapi.Schema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem(), output)
+	case reflect.String:
+		return &openapi.Schema{Type: "string"}
+	case reflect.Bool:
+		return &openapi.Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &openapi.Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &openapi.Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &openapi.Schema{Type: "array", Items: schemaOf(t.Elem(), output)}
+	case reflect.Struct:
+		return objectSchemaOf(t, output)
+	default:
+		return &openapi.Schema{}
+	}
+}
+
+// 構造体の JSON のフィールドから OpenAPI のオブジェクトのスキーマを生成します。
+func objectSchemaOf(t reflect.Type, output bool) *openapi.Schema {
+	rules := fieldRulesOf(t)
+	schema := &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := tagName(field, "json")
+		if name == "" || !field.IsExported() {
+			continue
+		}
+		property := schemaOf(field.Type, output)
+		required := applyRules(property, rules[field.Name])
+		if output && !strings.Contains(field.Tag.Get("json"), ",omitempty") {
+			required = true
+		}
+		schema.Properties[name] = property
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
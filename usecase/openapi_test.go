@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"nekonoshiri/go-echo-sample/openapi"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// コミットされている OpenAPI ドキュメントのパス。
+const openAPIDocumentPath = "../openapi.json"
+
+var updateOpenAPI = flag.Bool("update-openapi", false, "コミットされている OpenAPI ドキュメントを生成し直す")
+
+// コミットされている OpenAPI ドキュメントが、コードから生成したものと一致することのテスト。
+// 一致しない場合は `make openapi` で生成し直してください。
+func TestOpenAPIDocumentUpToDate(t *testing.T) {
+	generated, err := json.MarshalIndent(OpenAPIDocument(Routes()), "", "  ")
+	if err != nil {
+		t.Fatalf("OpenAPI ドキュメントのエンコードに失敗しました: %v", err)
+	}
+	generated = append(generated, '\n')
+
+	if *updateOpenAPI {
+		if err := os.WriteFile(openAPIDocumentPath, generated, 0o644); err != nil {
+			t.Fatalf("OpenAPI ドキュメントの書き込みに失敗しました: %v", err)
+		}
+		return
+	}
+
+	committed, err := os.ReadFile(openAPIDocumentPath)
+	if err != nil {
+		t.Fatalf("OpenAPI ドキュメントの読み込みに失敗しました: %v", err)
+	}
+	if diff := cmp.Diff(string(committed), string(generated)); diff != "" {
+		t.Errorf("コミットされている OpenAPI ドキュメント (-) とコードから生成したもの (+) が一致しません。"+
+			"`make openapi` で生成し直してください:\n%s", diff)
+	}
+}
+
+// バリデーションのルールが宣言した制約が、OpenAPI のスキーマの制約に変換されることのテスト。
+func TestOpenAPIDocumentConstraints(t *testing.T) {
+	document := OpenAPIDocument(Routes())
+
+	getUser := document.Paths["/users/{userID}"].Get
+	if getUser == nil {
+		t.Fatalf("GET /users/{userID} がありません")
+	}
+	intPtr := func(n int) *int { return &n }
+	wantParameters := []*openapi.Parameter{
+		{Name: "userID", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", MinLength: intPtr(1), MaxLength: intPtr(100)}},
+		{Name: "If-None-Match", In: "header", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "If-Modified-Since", In: "header", Schema: &openapi.Schema{Type: "string"}},
+	}
+	if diff := cmp.Diff(wantParameters, getUser.Parameters); diff != "" {
+		t.Errorf("期待されるパラメーター (-) と実際のパラメーター (+) が一致しません:\n%s", diff)
+	}
+
+	response := getUser.Responses["200"].Content["application/json"].Schema
+	wantStatus := &openapi.Schema{Type: "string", Enum: []interface{}{"pending", "normal", "frozen", "suspended", "closed"}}
+	if diff := cmp.Diff(wantStatus, response.Properties["status"]); diff != "" {
+		t.Errorf("期待される status のスキーマ (-) と実際のスキーマ (+) が一致しません:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"name", "status", "registeredAt"}, response.Required); diff != "" {
+		t.Errorf("期待される必須のフィールド (-) と実際の必須のフィールド (+) が一致しません:\n%s", diff)
+	}
+
+	if _, ok := getUser.Responses["404"]; !ok {
+		t.Errorf("UserNotFound エラーのレスポンス (404) がありません")
+	}
+	if _, ok := getUser.Responses["304"]; !ok {
+		t.Errorf("条件付き GET のレスポンス (304) がありません")
+	}
+
+	listUsers := document.Paths["/users"].Get
+	limit := listUsers.Parameters[0].Schema
+	if limit.Minimum == nil || *limit.Minimum != 1 || limit.Maximum == nil || *limit.Maximum != listUsersMaxLimit {
+		t.Errorf("limit の最小値・最大値が 1, %d ではありません: %+v", listUsersMaxLimit, limit)
+	}
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"nekonoshiri/go-echo-sample/logging"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+)
+
+// バリデーションできるリクエスト・レスポンスの構造体へのポインター。
+type validatable[T any] interface {
+	*T
+	validate() error
+}
+
+// レスポンスボディを返さない（HTTP ステータスコード 204 を返す）ユースケースのレスポンス。
+type noContent struct{}
+
+func (*noContent) validate() error {
+	return nil
+}
+
+// ユースケースの共通の処理を行い、ユースケースの本体 run を実行します。
+//  1. リクエスト（ヘッダーを含む）を Req にバインドします。失敗した場合は BadRequest エラーを返します。
+//  2. リクエストをバリデーションします。不正な場合はフィールド毎のエラーを含む BadRequest エラーを返します。
+//  3. run を実行します。run がエラーを返した場合は、そのエラーをそのまま返します。
+//     ただし、errNotModified の場合はレスポンスボディを返さずに 304 (Not Modified) を返します。
+//     run は、エラーコードに応じたエラーを返す場合は newErrorResponse 等を使用してください。
+//  4. run が返したレスポンスをバリデーションします。不正な場合は InternalServerError エラーを返します。
+//  5. レスポンスを spec.SuccessStatus で返します。Res が noContent の場合はレスポンスボディを返しません。
+//
+// リクエスト・レスポンスのボディは spec.BodyLog に従ってログに出力します。
+func handle[Req, Res any, PReq validatable[Req], PRes validatable[Res]](
+	c echo.Context,
+	spec *Spec,
+	run func(c echo.Context, request PReq) (PRes, error),
+) error {
+	ctx := c.Request().Context()
+
+	request := PReq(new(Req))
+	if err := c.Bind(request); err != nil {
+		return badRequest(c, msgBadRequest, err)
+	}
+	// Bind はヘッダーをバインドしないため、header タグのフィールドは別にバインドする
+	if err := (&echo.DefaultBinder{}).BindHeaders(c, request); err != nil {
+		return badRequest(c, msgBadRequest, err)
+	}
+	logging.LogRequestBody(ctx, spec.BodyLog, request)
+	if err := request.validate(); err != nil {
+		if errs, ok := err.(validation.Errors); ok {
+			return invalidRequest(c, request, errs)
+		}
+		return internalServerError(c, msgRequestValidationFailed, err)
+	}
+
+	response, err := run(c, request)
+	if errors.Is(err, errNotModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		return internalServerError(c, msgResponseValidationFailed, errors.New("レスポンスが nil です"))
+	}
+	if err := response.validate(); err != nil {
+		return internalServerError(c, msgResponseValidationFailed, fmt.Errorf("%+v: %w", *response, err))
+	}
+
+	if _, ok := interface{}(response).(*noContent); ok {
+		return c.NoContent(spec.SuccessStatus)
+	}
+	logging.LogResponseBody(ctx, spec.BodyLog, response)
+	return c.JSON(spec.SuccessStatus, response)
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+// テスト用のリクエスト。
+type pipelineTestRequest struct {
+	Value string `json:"value"`
+}
+
+func (request *pipelineTestRequest) validate() error {
+	return validation.ValidateStruct(request,
+		validation.Field(&request.Value, validation.Required.Error(msgNameRequired)),
+	)
+}
+
+// テスト用のレスポンス。
+type pipelineTestResponse struct {
+	Value string `json:"value"`
+}
+
+func (response *pipelineTestResponse) validate() error {
+	return validation.ValidateStruct(response,
+		validation.Field(&response.Value, validation.Required.Error(msgNameRequired)),
+	)
+}
+
+// テスト用に、JSON のリクエストボディを持つ echo のコンテキストを作成します。
+func newPipelineTestContext(body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+	return e.NewContext(request, recorder), recorder
+}
+
+// ユースケースの共通の処理の正常系のテスト。
+func TestHandleOK(t *testing.T) {
+	c, recorder := newPipelineTestContext(`{"value": "リクエスト"}`)
+
+	err := handle(c, &Spec{SuccessStatus: http.StatusCreated}, func(c echo.Context, request *pipelineTestRequest) (*pipelineTestResponse, error) {
+		return &pipelineTestResponse{Value: request.Value + "への応答"}, nil
+	})
+	if err != nil {
+		t.Fatalf("エラーが返りました: %v", err)
+	}
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusCreated, recorder.Code)
+	}
+	if diff := cmp.Diff(`{"value": "リクエストへの応答"}`, recorder.Body.String(), UnmarshalJSON); diff != "" {
+		t.Errorf("期待されるレスポンスボディ (-) と実際のレスポンスボディ (+) が一致しませんでした:\n%s", diff)
+	}
+}
+
+// レスポンスボディを返さないユースケースのテスト。
+func TestHandleNoContent(t *testing.T) {
+	c, recorder := newPipelineTestContext(`{"value": "リクエスト"}`)
+
+	err := handle(c, &Spec{SuccessStatus: http.StatusNoContent}, func(c echo.Context, request *pipelineTestRequest) (*noContent, error) {
+		return &noContent{}, nil
+	})
+	if err != nil {
+		t.Fatalf("エラーが返りました: %v", err)
+	}
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNoContent, recorder.Code)
+	}
+	if recorder.Body.Len() != 0 {
+		t.Errorf("レスポンスボディは空のはずですが、%q が返りました", recorder.Body.String())
+	}
+}
+
+// ユースケースの共通の処理のエラー系のテスト。
+func TestHandleError(t *testing.T) {
+	errUseCase := echo.NewHTTPError(http.StatusConflict, &ErrorResponse{
+		Code:    ErrorCodeUserFrozen,
+		Message: "ユーザーが凍結状態です",
+		Err:     errors.New("ユースケースのエラー"),
+	})
+
+	testCases := []struct {
+		name           string                                                                  // テストケース名
+		body           string                                                                  // リクエストボディ
+		run            func(echo.Context, *pipelineTestRequest) (*pipelineTestResponse, error) // ユースケースの本体
+		wantStatusCode int                                                                     // 期待される HTTP ステータスコード
+		wantErrorCode  string                                                                  // 期待されるエラーコード
+	}{
+		{
+			name:           "バインドに失敗",
+			body:           `{"value": 1}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "BadRequest",
+		},
+		{
+			name:           "リクエストが不正",
+			body:           `{"value": ""}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "BadRequest",
+		},
+		{
+			name: "ユースケースの本体がエラーを返す",
+			body: `{"value": "リクエスト"}`,
+			run: func(echo.Context, *pipelineTestRequest) (*pipelineTestResponse, error) {
+				return nil, errUseCase
+			},
+			wantStatusCode: http.StatusConflict,
+			wantErrorCode:  "UserFrozen",
+		},
+		{
+			name: "レスポンスが不正",
+			body: `{"value": "リクエスト"}`,
+			run: func(echo.Context, *pipelineTestRequest) (*pipelineTestResponse, error) {
+				return &pipelineTestResponse{}, nil
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantErrorCode:  "InternalServerError",
+		},
+		{
+			name: "レスポンスが nil",
+			body: `{"value": "リクエスト"}`,
+			run: func(echo.Context, *pipelineTestRequest) (*pipelineTestResponse, error) {
+				return nil, nil
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantErrorCode:  "InternalServerError",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := newPipelineTestContext(tc.body)
+			run := tc.run
+			if run == nil {
+				run = func(echo.Context, *pipelineTestRequest) (*pipelineTestResponse, error) {
+					t.Fatalf("ユースケースの本体が実行されました")
+					return nil, nil
+				}
+			}
+
+			err := handle(c, &Spec{SuccessStatus: http.StatusOK}, run)
+			if err == nil {
+				t.Fatalf("エラーを返すはずですが、返しませんでした")
+			}
+
+			statusCode, errorResponse := ParseErrorResponse(t, err)
+			if statusCode != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, statusCode)
+			}
+			if errorResponse.Code != tc.wantErrorCode {
+				t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", tc.wantErrorCode, errorResponse.Code)
+			}
+		})
+	}
+}
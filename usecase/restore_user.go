@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RestoreUser ユースケースのリクエスト。
+type RestoreUserRequest struct {
+	// ユーザー ID。必須で、1 文字以上 100 文字以下です。
+	UserID string `param:"userID"`
+	// If-Match ヘッダー。省略可能で、指定した場合はユーザーの ETag と一致しなければ PreconditionFailed エラーを返します。
+	IfMatch string `header:"If-Match" json:"-"`
+}
+
+func (request *RestoreUserRequest) validate() error {
+	return validateStruct(request, request.fieldRules())
+}
+
+func (request *RestoreUserRequest) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&request.UserID,
+			required(msgUserIDRequired),
+			runeLength(1, 100, msgUserIDLength),
+		),
+	}
+}
+
+// RestoreUser ユースケースの仕様。
+var restoreUserSpec = &Spec{
+	Name:           "RestoreUser",
+	Method:         http.MethodPost,
+	Path:           "/users/:userID/restore",
+	Summary:        "削除済みのユーザーを復元します",
+	Description:    "削除済みのユーザーは、完全に削除されるまでの保持期間中であれば復元できます。ユーザーが削除済みでない場合は何もしません。",
+	Request:        &RestoreUserRequest{},
+	SuccessStatus:  http.StatusNoContent,
+	ErrorCodes:     []string{ErrorCodeBadRequest, ErrorCodeUserNotFound, ErrorCodeUserConflict, ErrorCodePreconditionFailed, ErrorCodeInternalServerError},
+	Permissions:    []Permission{PermissionWriteUsers},
+	RateLimitClass: RateLimitClassWrite,
+}
+
+// RestoreUser ユースケース。削除済みのユーザーを復元します。
+//   - リクエスト: [RestoreUserRequest]
+//   - レスポンス: なし（HTTP ステータスコード 204）
+//
+// 削除済みのユーザーは、完全に削除されるまでの保持期間中であれば復元できます。
+// ユーザーが削除済みでない場合は何もしません。
+//
+// 成功した場合は、更新後のユーザーの ETag を ETag ヘッダーに設定します。
+// If-Match ヘッダーを指定すると、ユーザーがその ETag から更新されていない場合のみ実行します（削除するとバージョンが変わることに注意してください）。
+//
+// このユースケースは、以下のエラーコードを返します。
+//   - BadRequest: リクエストが不正な場合。
+//   - UserNotFound: ユーザーが見つからなかった（完全に削除された場合を含む）場合。
+//   - UserConflict: ユーザーを取得してから保存するまでの間に、他の操作によってユーザーが更新された場合。
+//   - PreconditionFailed: If-Match ヘッダーがユーザーの ETag と一致しない場合。
+//   - InternalServerError: サーバーエラーが発生した場合。
+func RestoreUser(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error {
+	return handle(c, restoreUserSpec, func(c echo.Context, request *RestoreUserRequest) (*noContent, error) {
+		ctx := c.Request().Context()
+
+		user, err := userRepository.GetIncludingDeleted(ctx, request.UserID)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return nil, userNotFound(c, err)
+			}
+			return nil, internalServerError(c, msgGetUserFailed, err)
+		}
+
+		if !matchesIfMatch(request.IfMatch, userETag(user)) {
+			return nil, preconditionFailed(c, fmt.Errorf("If-Match %s がユーザーの ETag %s と一致しません", request.IfMatch, userETag(user)))
+		}
+
+		// 削除済みでない場合は、バージョン（ETag）が変わらないように保存しない
+		if user.Restore(clock) {
+			if err := userRepository.Put(ctx, user); err != nil {
+				return nil, saveUserFailed(c, err)
+			}
+		}
+
+		c.Response().Header().Set(headerETag, userETag(user))
+		return &noContent{}, nil
+	})
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+	"nekonoshiri/go-echo-sample/infra"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RestoreUser ユースケースのテスト用の echo.Context を作成します。
+func newRestoreUserContext(userID string, ifMatch string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodPost, "/users/:userID/restore", nil)
+	if ifMatch != "" {
+		request.Header.Set("If-Match", ifMatch)
+	}
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+	c.SetParamNames("userID")
+	c.SetParamValues(userID)
+	return c, recorder
+}
+
+// RestoreUser ユースケースの正常系のテスト。
+func TestRestoreUserOK(t *testing.T) {
+	ctx := context.Background()
+	userRepository := infra.NewMemoryUserRepository()
+	user := domain.User{
+		UserID:       "U1",
+		Name:         "ユーザー１",
+		Status:       domain.UserStatusNormal,
+		RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := userRepository.Put(ctx, &user); err != nil {
+		t.Fatalf("ユーザーの保存に失敗しました: %v", err)
+	}
+	if _, err := userRepository.Delete(ctx, "U1", time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("ユーザーの削除に失敗しました: %v", err)
+	}
+
+	// 削除によってバージョンは 2 になっている
+	c, recorder := newRestoreUserContext("U1", `"2"`)
+	if err := RestoreUser(c, userRepository, domain.SystemClock); err != nil {
+		t.Fatalf("ユースケースがエラーを返しました: %v", err)
+	}
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNoContent, recorder.Code)
+	}
+	if etag := recorder.Header().Get("ETag"); etag != `"3"` {
+		t.Errorf("ETag ヘッダーは更新後のバージョンの %q のはずですが、%q が返りました", `"3"`, etag)
+	}
+
+	restoredUser, err := userRepository.Get(ctx, "U1")
+	if err != nil {
+		t.Fatalf("復元したユーザーを取得しようとしましたが、エラーが発生しました: %v", err)
+	}
+	if restoredUser.Name != "ユーザー１" {
+		t.Errorf("復元したユーザーの名前は %q のはずですが、%q となっています", "ユーザー１", restoredUser.Name)
+	}
+
+	// 削除済みでないユーザーを復元しても成功し、何も変わらないのでバージョン（ETag）も変わらない
+	c, recorder = newRestoreUserContext("U1", "")
+	if err := RestoreUser(c, userRepository, domain.SystemClock); err != nil {
+		t.Fatalf("削除済みでないユーザーを復元しようとしたところ、ユースケースがエラーを返しました: %v", err)
+	}
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNoContent, recorder.Code)
+	}
+	if etag := recorder.Header().Get("ETag"); etag != `"3"` {
+		t.Errorf("ETag ヘッダーは変わらず %q のはずですが、%q が返りました", `"3"`, etag)
+	}
+}
+
+// RestoreUser ユースケースのエラー系のテスト。
+func TestRestoreUserError(t *testing.T) {
+	deletedAt := time.Date(1000, time.January, 2, 0, 0, 0, 0, time.UTC)
+	deletedUser := func(ctx context.Context, userID string) (*domain.User, error) {
+		return &domain.User{
+			UserID:       userID,
+			Name:         "ユーザー１",
+			Status:       domain.UserStatusNormal,
+			RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Version:      2,
+			UpdatedAt:    deletedAt,
+			DeletedAt:    &deletedAt,
+		}, nil
+	}
+	notFound := func(ctx context.Context, userID string) (*domain.User, error) {
+		return nil, domain.ErrUserNotFound
+	}
+	conflict := func(ctx context.Context, user *domain.User) error {
+		return domain.ErrConflict
+	}
+
+	testCases := []struct {
+		name                string                                                         // テストケース名
+		userID              string                                                         // 復元しようとするユーザーの ID
+		ifMatch             string                                                         // If-Match ヘッダー
+		getIncludingDeleted func(ctx context.Context, userID string) (*domain.User, error) // リポジトリの GetIncludingDeleted
+		put                 func(ctx context.Context, user *domain.User) error             // リポジトリの Put
+		wantStatusCode      int                                                            // 期待される HTTP ステータスコード
+		wantErrorCode       string                                                         // 期待されるエラーコード
+	}{
+		{
+			name:           "ユーザー ID が空文字列の場合",
+			userID:         "",
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "BadRequest",
+		},
+		{
+			name:                "ユーザーが見つからない場合",
+			userID:              "U1",
+			getIncludingDeleted: notFound,
+			wantStatusCode:      http.StatusNotFound,
+			wantErrorCode:       "UserNotFound",
+		},
+		{
+			name:                "If-Match が削除前の ETag の場合",
+			userID:              "U1",
+			ifMatch:             `"1"`,
+			getIncludingDeleted: deletedUser,
+			wantStatusCode:      http.StatusPreconditionFailed,
+			wantErrorCode:       "PreconditionFailed",
+		},
+		{
+			name:                "取得してから保存するまでの間に他の操作でユーザーが更新された場合",
+			userID:              "U1",
+			getIncludingDeleted: deletedUser,
+			put:                 conflict,
+			wantStatusCode:      http.StatusConflict,
+			wantErrorCode:       "UserConflict",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepository := &MockUserRepository{getIncludingDeleted: tc.getIncludingDeleted, put: tc.put}
+
+			c, _ := newRestoreUserContext(tc.userID, tc.ifMatch)
+			err := RestoreUser(c, userRepository, domain.SystemClock)
+			if err == nil {
+				t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+			}
+
+			statusCode, errorResponse := ParseErrorResponse(t, err)
+			if statusCode != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, statusCode)
+			}
+			if errorResponse.Code != tc.wantErrorCode {
+				t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", tc.wantErrorCode, errorResponse.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+)
+
+// ルート。ユースケースの仕様と、ユースケースを実行するハンドラーの組です。
+type Route struct {
+	*Spec
+	// ユースケースを実行するハンドラー。clock は、現在日時を使う処理（日時の記録や期限の判定）に使用します。
+	Handler func(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error
+}
+
+// すべてのルートを返します。ユースケースを追加する場合は、ここにルートを追加してください。
+// サーバーのルーティング（[RegisterRoutes]）と OpenAPI ドキュメントの生成（[OpenAPIDocument]）は、このルートの一覧から行います。
+func Routes() []*Route {
+	return []*Route{
+		{Spec: listUsersSpec, Handler: ListUsers},
+		{Spec: createUserSpec, Handler: CreateUser},
+		{Spec: getUserSpec, Handler: GetUser},
+		{Spec: deleteUserSpec, Handler: DeleteUser},
+		{Spec: restoreUserSpec, Handler: RestoreUser},
+		{Spec: changeUserNameSpec, Handler: ChangeUserName},
+		{Spec: freezeUserSpec, Handler: FreezeUser},
+		{Spec: unfreezeUserSpec, Handler: UnfreezeUser},
+	}
+}
+
+// クライアント毎のレート制限。
+type RateLimit struct {
+	// 1 秒あたりに許可するリクエスト数。
+	Rate float64
+	// 一時的に Rate を超えて許可するリクエスト数。
+	Burst int
+}
+
+// ルートを e に登録します。各ハンドラーには userRepository と clock を渡します。
+// ルート毎に、レート制限の種類に応じて rateLimits のレート制限を行います（クライアントは IP アドレスで識別します）。
+// rateLimits にレート制限の種類が含まれない場合、そのルートはレート制限を行いません。
+//
+// 権限（Permissions）は、現時点ではドキュメントにのみ使用し、認可は行いません。
+func RegisterRoutes(e *echo.Echo, routes []*Route, userRepository domain.UserRepository, clock domain.Clock, rateLimits map[RateLimitClass]RateLimit) {
+	// 同じ種類のルートはストアを共有する
+	rateLimiters := map[RateLimitClass]echo.MiddlewareFunc{}
+	for class, limit := range rateLimits {
+		rateLimiters[class] = middleware.RateLimiter(middleware.NewRateLimiterMemoryStoreWithConfig(
+			middleware.RateLimiterMemoryStoreConfig{Rate: rate.Limit(limit.Rate), Burst: limit.Burst},
+		))
+	}
+
+	for _, route := range routes {
+		handler := route.Handler
+		var middlewares []echo.MiddlewareFunc
+		if rateLimiter, ok := rateLimiters[route.RateLimitClass]; ok {
+			middlewares = append(middlewares, rateLimiter)
+		}
+		r := e.Add(route.Method, route.Path, func(c echo.Context) error {
+			return handler(c, userRepository, clock)
+		}, middlewares...)
+		r.Name = route.Name
+	}
+}
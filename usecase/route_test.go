@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
+)
+
+// すべてのルートが必要なメタデータを宣言していることのテスト。
+func TestRoutes(t *testing.T) {
+	names := map[string]bool{}
+	endpoints := map[string]bool{}
+	rateLimitClasses := map[RateLimitClass]bool{RateLimitClassRead: true, RateLimitClassWrite: true}
+
+	for _, route := range Routes() {
+		t.Run(route.Name, func(t *testing.T) {
+			if route.Name == "" || names[route.Name] {
+				t.Errorf("ユースケース名 %q が空か、重複しています", route.Name)
+			}
+			names[route.Name] = true
+
+			endpoint := route.Method + " " + route.Path
+			if endpoints[endpoint] {
+				t.Errorf("%s が重複しています", endpoint)
+			}
+			endpoints[endpoint] = true
+
+			if route.Handler == nil {
+				t.Errorf("ハンドラーがありません")
+			}
+			if route.Request == nil {
+				t.Errorf("リクエストの型がありません")
+			}
+			if route.SuccessStatus == 0 {
+				t.Errorf("成功した場合の HTTP ステータスコードがありません")
+			}
+			if len(route.Permissions) == 0 {
+				t.Errorf("必要な権限がありません")
+			}
+			if !rateLimitClasses[route.RateLimitClass] {
+				t.Errorf("レート制限の種類 %q が不明です（設定でレート制限を指定できません）", route.RateLimitClass)
+			}
+			for _, code := range route.ErrorCodes {
+				if _, ok := errorCodes[code]; !ok {
+					t.Errorf("エラーコード %s が登録されていません", code)
+				}
+			}
+		})
+	}
+}
+
+// ルートが echo に登録されることのテスト。
+func TestRegisterRoutes(t *testing.T) {
+	e := echo.New()
+	routes := Routes()
+	RegisterRoutes(e, routes, &MockUserRepository{}, domain.SystemClock, nil)
+
+	want := []string{}
+	for _, route := range routes {
+		want = append(want, route.Method+" "+route.Path+" "+route.Name)
+	}
+	got := []string{}
+	for _, route := range e.Routes() {
+		got = append(got, route.Method+" "+route.Path+" "+route.Name)
+	}
+	sort.Strings(want)
+	sort.Strings(got)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("期待されるルート (-) と登録されたルート (+) が一致しません:\n%s", diff)
+	}
+}
+
+// レート制限を超えた場合に TooManyRequests エラーを返すことのテスト。
+func TestRegisterRoutesRateLimit(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = NewHTTPErrorHandler(false)
+	routes := []*Route{
+		{
+			Spec: &Spec{Name: "Limited", Method: http.MethodGet, Path: "/limited", RateLimitClass: RateLimitClassWrite},
+			Handler: func(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error {
+				return c.NoContent(http.StatusNoContent)
+			},
+		},
+		{
+			Spec: &Spec{Name: "Unlimited", Method: http.MethodGet, Path: "/unlimited", RateLimitClass: RateLimitClassRead},
+			Handler: func(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error {
+				return c.NoContent(http.StatusNoContent)
+			},
+		},
+	}
+	RegisterRoutes(e, routes, &MockUserRepository{}, domain.SystemClock, map[RateLimitClass]RateLimit{
+		RateLimitClassWrite: {Rate: 0.001, Burst: 1},
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	if recorder := serve("/limited"); recorder.Code != http.StatusNoContent {
+		t.Fatalf("1 回目のリクエストは成功するはずですが、%d が返りました", recorder.Code)
+	}
+	recorder := serve("/limited")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("2 回目のリクエストは %d を返すはずですが、%d が返りました", http.StatusTooManyRequests, recorder.Code)
+	}
+	var response ErrorResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("エラーレスポンスのデコードに失敗しました: %v", err)
+	}
+	if response.Code != ErrorCodeTooManyRequests {
+		t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", ErrorCodeTooManyRequests, response.Code)
+	}
+
+	// レート制限の設定がない種類のルートは制限しない
+	for i := 0; i < 3; i++ {
+		if recorder := serve("/unlimited"); recorder.Code != http.StatusNoContent {
+			t.Fatalf("レート制限のないルートが %d を返しました", recorder.Code)
+		}
+	}
+}
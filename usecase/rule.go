@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"reflect"
+
+	"nekonoshiri/go-echo-sample/openapi"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// リクエスト・レスポンスのフィールドと、そのバリデーションのルール。
+// validation.FieldRules と異なり、OpenAPI ドキュメントの生成のためにフィールドとルールを参照できます。
+type fieldRule struct {
+	// フィールドへのポインター。
+	fieldPtr interface{}
+	// フィールドのルール。
+	rules []validation.Rule
+}
+
+// フィールドとルールの組を作ります。validation.Field の代わりに使用してください。
+func field(fieldPtr interface{}, rules ...validation.Rule) *fieldRule {
+	return &fieldRule{fieldPtr: fieldPtr, rules: rules}
+}
+
+// 構造体のフィールドをルールに従って検証します。エラーは validation.ValidateStruct と同じです。
+func validateStruct(structPtr interface{}, fields []*fieldRule) error {
+	fieldRules := make([]*validation.FieldRules, 0, len(fields))
+	for _, f := range fields {
+		fieldRules = append(fieldRules, validation.Field(f.fieldPtr, f.rules...))
+	}
+	return validation.ValidateStruct(structPtr, fieldRules...)
+}
+
+// OpenAPI のスキーマの制約を宣言したルール。
+//
+// ozzo-validation のルールは制約の値（最小値など）を公開していないため、OpenAPI ドキュメントに反映したいルールは、
+// ozzo-validation のルールを直接使わずに、このファイルの関数で作成してください。
+// 制約とバリデーションのルールは同じ引数から作られるので、ドキュメントとバリデーションが食い違うことはありません。
+// 直接使った ozzo-validation のルール（validation.By 等）は、OpenAPI ドキュメントには反映されません。
+type constraintRule struct {
+	validation.Rule
+	// 必須のルールかどうか。
+	required bool
+	// スキーマに制約を設定します。nil の場合は何もしません。
+	apply func(schema *openapi.Schema)
+}
+
+// 値が空でないことを検証するルール。必須のフィールドになります。
+func required(message string) *constraintRule {
+	return &constraintRule{Rule: validation.Required.Error(message), required: true}
+}
+
+// 値が nil でないことを検証するルール。必須のフィールドになります。
+func notNil(message string) *constraintRule {
+	return &constraintRule{Rule: validation.NotNil.Error(message), required: true}
+}
+
+// 文字列の文字数が min 以上 max 以下であることを検証するルール。minLength, maxLength になります。
+// 空文字列は検証しないので、必要であれば required と組み合わせてください。
+func runeLength(min, max int, message string) *constraintRule {
+	return &constraintRule{
+		Rule: validation.RuneLength(min, max).Error(message),
+		apply: func(schema *openapi.Schema) {
+			schema.MinLength = &min
+			schema.MaxLength = &max
+		},
+	}
+}
+
+// 値が values のいずれかであることを検証するルール。enum になります。
+// 空の値は検証しないので、必要であれば required と組み合わせてください。
+func in(message string, values ...interface{}) *constraintRule {
+	return &constraintRule{
+		Rule: validation.In(values...).Error(message),
+		apply: func(schema *openapi.Schema) {
+			schema.Enum = append([]interface{}{}, values...)
+		},
+	}
+}
+
+// 整数が min 以上であることを検証するルール。minimum になります。
+// 0 は検証しないので、必要であれば他のルールと組み合わせてください。
+func minimum(min int, message string) *constraintRule {
+	return &constraintRule{
+		Rule: validation.Min(min).Error(message),
+		apply: func(schema *openapi.Schema) {
+			number := float64(min)
+			schema.Minimum = &number
+		},
+	}
+}
+
+// 整数が max 以下であることを検証するルール。maximum になります。
+func maximum(max int, message string) *constraintRule {
+	return &constraintRule{
+		Rule: validation.Max(max).Error(message),
+		apply: func(schema *openapi.Schema) {
+			number := float64(max)
+			schema.Maximum = &number
+		},
+	}
+}
+
+// ルールの制約を schema に設定します。必須のルールがある場合は true を返します。
+// constraintRule でないルールは無視します。
+func applyRules(schema *openapi.Schema, rules []validation.Rule) bool {
+	required := false
+	for _, rule := range rules {
+		constraint, ok := rule.(*constraintRule)
+		if !ok {
+			continue
+		}
+		if constraint.required {
+			required = true
+		}
+		if constraint.apply != nil {
+			constraint.apply(schema)
+		}
+	}
+	return required
+}
+
+// バリデーションのルールを持つリクエスト・レスポンスの構造体。
+type fieldRuler interface {
+	fieldRules() []*fieldRule
+}
+
+// 構造体の fieldRules が返すルールを、フィールド名毎に返します。
+// 構造体が fieldRules を持たない場合は空のマップを返します。
+func fieldRulesOf(t reflect.Type) map[string][]validation.Rule {
+	rulesByField := map[string][]validation.Rule{}
+	value := reflect.New(t)
+	ruler, ok := value.Interface().(fieldRuler)
+	if !ok {
+		return rulesByField
+	}
+
+	// フィールドへのポインターのアドレスから、フィールド名を求める
+	fieldNames := map[uintptr]string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldNames[value.Pointer()+f.Offset] = f.Name
+	}
+
+	for _, f := range ruler.fieldRules() {
+		name, ok := fieldNames[reflect.ValueOf(f.fieldPtr).Pointer()]
+		if !ok {
+			continue
+		}
+		rulesByField[name] = append(rulesByField[name], f.rules...)
+	}
+	return rulesByField
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"testing"
+
+	"nekonoshiri/go-echo-sample/openapi"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/go-cmp/cmp"
+)
+
+// ルールが宣言した制約と、実際のバリデーションの結果が一致することのテスト。
+// ozzo-validation を更新してバリデーションの挙動が変わった場合に、OpenAPI ドキュメントとの食い違いを検出します。
+func TestConstraintRulesMatchValidation(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	floatPtr := func(n float64) *float64 { return &n }
+	testCases := []struct {
+		name    string          // テストケース名
+		rule    *constraintRule // テストするルール
+		schema  *openapi.Schema // 期待される制約
+		valid   []interface{}   // 制約を満たす値
+		invalid []interface{}   // 制約を満たさない値
+	}{
+		{
+			name:    "runeLength",
+			rule:    runeLength(2, 3, "message"),
+			schema:  &openapi.Schema{MinLength: intPtr(2), MaxLength: intPtr(3)},
+			valid:   []interface{}{"あい", "あいう"},
+			invalid: []interface{}{"あ", "あいうえ"},
+		},
+		{
+			name:    "in",
+			rule:    in("message", "a", "b"),
+			schema:  &openapi.Schema{Enum: []interface{}{"a", "b"}},
+			valid:   []interface{}{"a", "b"},
+			invalid: []interface{}{"c"},
+		},
+		{
+			name:    "minimum",
+			rule:    minimum(2, "message"),
+			schema:  &openapi.Schema{Minimum: floatPtr(2)},
+			valid:   []interface{}{2, 3},
+			invalid: []interface{}{1, -1},
+		},
+		{
+			name:    "maximum",
+			rule:    maximum(2, "message"),
+			schema:  &openapi.Schema{Maximum: floatPtr(2)},
+			valid:   []interface{}{1, 2},
+			invalid: []interface{}{3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schema := &openapi.Schema{}
+			if applyRules(schema, []validation.Rule{tc.rule}) {
+				t.Errorf("必須でないルールですが、必須と判定されました")
+			}
+			if diff := cmp.Diff(tc.schema, schema); diff != "" {
+				t.Errorf("期待される制約 (-) と実際の制約 (+) が一致しません:\n%s", diff)
+			}
+
+			for _, value := range tc.valid {
+				if err := validation.Validate(value, tc.rule); err != nil {
+					t.Errorf("%v は制約を満たすはずですが、エラーになりました: %v", value, err)
+				}
+			}
+			for _, value := range tc.invalid {
+				if err := validation.Validate(value, tc.rule); err == nil {
+					t.Errorf("%v は制約を満たさないはずですが、エラーになりませんでした", value)
+				}
+			}
+		})
+	}
+}
+
+// 必須のルールが、必須と判定され、空の値をエラーにすることのテスト。
+func TestRequiredRules(t *testing.T) {
+	for name, rule := range map[string]*constraintRule{"required": required("message"), "notNil": notNil("message")} {
+		t.Run(name, func(t *testing.T) {
+			if !applyRules(&openapi.Schema{}, []validation.Rule{rule}) {
+				t.Errorf("必須のルールですが、必須と判定されませんでした")
+			}
+			if err := validation.Validate([]string(nil), rule); err == nil {
+				t.Errorf("nil はエラーになるはずですが、エラーになりませんでした")
+			}
+		})
+	}
+
+	// 制約を宣言していないルールは無視される
+	if applyRules(&openapi.Schema{}, []validation.Rule{validation.Required}) {
+		t.Errorf("ozzo-validation のルールを直接使った場合は無視されるはずですが、必須と判定されました")
+	}
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"nekonoshiri/go-echo-sample/logging"
+)
+
+// 権限。
+type Permission string
+
+const (
+	// ユーザーを参照する権限。
+	PermissionReadUsers Permission = "users:read"
+	// ユーザーを作成・変更・削除する権限。
+	PermissionWriteUsers Permission = "users:write"
+	// ユーザーを凍結・凍結解除する権限。
+	PermissionModerateUsers Permission = "users:moderate"
+)
+
+// レート制限の種類。同じ種類のユースケースは、クライアント毎のレート制限を共有します。
+type RateLimitClass string
+
+const (
+	// 参照系のユースケース。
+	RateLimitClassRead RateLimitClass = "read"
+	// 更新系のユースケース。
+	RateLimitClassWrite RateLimitClass = "write"
+)
+
+// ユーザーを扱うユースケースのボディのログの設定。
+// 名前と、ステータス変更の操作者の ID・理由は個人情報（を含みうる）ため、リクエスト・レスポンスのどちらでも伏せます。
+// ユーザー一覧のレスポンスは大きくなりうるので切り詰めます。
+var userBodyLog = logging.BodyLogConfig{
+	Enabled:      true,
+	MaxBytes:     4096,
+	RedactFields: []string{"name", "operatorID", "reason"},
+}
+
+// ユースケースの仕様。ユースケースの共通の処理や、OpenAPI ドキュメントの生成に使用します。
+type Spec struct {
+	// ユースケース名。OpenAPI の operationId になります。
+	Name string
+	// HTTP メソッド。
+	Method string
+	// パス。echo の形式です（例: /users/:userID）。
+	Path string
+	// ユースケースの概要。
+	Summary string
+	// ユースケースの説明。
+	Description string
+	// リクエストの構造体のゼロ値へのポインター。型の情報のみを使用します。
+	Request interface{}
+	// レスポンスの構造体のゼロ値へのポインター。レスポンスボディを返さない場合は nil です。
+	Response interface{}
+	// 成功した場合の HTTP ステータスコード。
+	SuccessStatus int
+	// ユースケースが返すエラーコード。
+	ErrorCodes []string
+	// ユースケースの実行に必要な権限。
+	Permissions []Permission
+	// レート制限の種類。
+	RateLimitClass RateLimitClass
+	// リクエスト・レスポンスのボディのログの設定。
+	BodyLog logging.BodyLogConfig
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UnfreezeUser ユースケースのリクエスト。
+type UnfreezeUserRequest struct {
+	// ユーザー ID。必須で、1 文字以上 100 文字以下です。
+	UserID string `param:"userID"`
+	// 凍結解除を行う操作者の ID。必須で、1 文字以上 100 文字以下です。
+	OperatorID string `json:"operatorID"`
+	// 凍結解除の理由。必須で、1 文字以上 1000 文字以下です。
+	Reason string `json:"reason"`
+	// If-Match ヘッダー。省略可能で、指定した場合はユーザーの ETag と一致しなければ PreconditionFailed エラーを返します。
+	IfMatch string `header:"If-Match" json:"-"`
+}
+
+func (request *UnfreezeUserRequest) validate() error {
+	return validateStruct(request, request.fieldRules())
+}
+
+func (request *UnfreezeUserRequest) fieldRules() []*fieldRule {
+	return []*fieldRule{
+		field(&request.UserID,
+			required(msgUserIDRequired),
+			runeLength(1, 100, msgUserIDLength),
+		),
+		field(&request.OperatorID,
+			required(msgOperatorIDRequired),
+			runeLength(1, 100, msgOperatorIDLength),
+		),
+		field(&request.Reason,
+			required(msgReasonRequired),
+			runeLength(1, 1000, msgReasonLength),
+		),
+	}
+}
+
+// UnfreezeUser ユースケースの仕様。
+var unfreezeUserSpec = &Spec{
+	Name:           "UnfreezeUser",
+	Method:         http.MethodPost,
+	Path:           "/users/:userID/unfreeze",
+	Summary:        "ユーザーの凍結状態を解除します",
+	Description:    "操作者の ID と理由は、ユーザーのステータス変更の記録として残ります。ユーザーが凍結状態でない場合は何もしません。",
+	Request:        &UnfreezeUserRequest{},
+	SuccessStatus:  http.StatusNoContent,
+	ErrorCodes:     []string{ErrorCodeBadRequest, ErrorCodeUserNotFound, ErrorCodeUserConflict, ErrorCodePreconditionFailed, ErrorCodeInternalServerError},
+	Permissions:    []Permission{PermissionModerateUsers},
+	RateLimitClass: RateLimitClassWrite,
+	BodyLog:        userBodyLog,
+}
+
+// UnfreezeUser ユースケース。ユーザーの凍結状態を解除します。
+//   - リクエスト: [UnfreezeUserRequest]
+//   - レスポンス: なし（HTTP ステータスコード 204）
+//
+// 成功した場合は、更新後のユーザーの ETag を ETag ヘッダーに設定します。
+// If-Match ヘッダーを指定すると、ユーザーがその ETag から更新されていない場合のみ実行します。
+//
+// 操作者の ID と理由は、ユーザーのステータス変更の記録として残ります。
+// ユーザーが凍結状態でない場合は何もしません（ステータス変更の記録も更新しません）。
+//
+// このユースケースは、以下のエラーコードを返します。
+//   - BadRequest: リクエストが不正な場合。
+//   - UserNotFound: ユーザーが見つからなかった場合。
+//   - UserConflict: ユーザーを取得してから保存するまでの間に、他の操作によってユーザーが更新された場合。
+//   - PreconditionFailed: If-Match ヘッダーがユーザーの ETag と一致しない場合。
+//   - InternalServerError: サーバーエラーが発生した場合。
+func UnfreezeUser(c echo.Context, userRepository domain.UserRepository, clock domain.Clock) error {
+	return handle(c, unfreezeUserSpec, func(c echo.Context, request *UnfreezeUserRequest) (*noContent, error) {
+		ctx := c.Request().Context()
+
+		user, err := userRepository.Get(ctx, request.UserID)
+		if err != nil {
+			if errors.Is(err, domain.ErrUserNotFound) {
+				return nil, userNotFound(c, err)
+			}
+			return nil, internalServerError(c, msgGetUserFailed, err)
+		}
+
+		if !matchesIfMatch(request.IfMatch, userETag(user)) {
+			return nil, preconditionFailed(c, fmt.Errorf("If-Match %s がユーザーの ETag %s と一致しません", request.IfMatch, userETag(user)))
+		}
+
+		changed, err := user.Unfreeze(request.OperatorID, request.Reason, clock)
+		if err != nil {
+			return nil, changeUserStatusFailed(c, err)
+		}
+
+		// 何も変わらない場合は、バージョン（ETag）が変わらないように保存しない
+		if changed {
+			if err := userRepository.Put(ctx, user); err != nil {
+				return nil, saveUserFailed(c, err)
+			}
+		}
+
+		c.Response().Header().Set(headerETag, userETag(user))
+		return &noContent{}, nil
+	})
+}
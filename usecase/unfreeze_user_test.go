@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nekonoshiri/go-echo-sample/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UnfreezeUser ユースケースの正常系のテスト。
+func TestUnfreezeUserOK(t *testing.T) {
+	var savedUser *domain.User
+	userRepository := &MockUserRepository{
+		get: func(ctx context.Context, userID string) (*domain.User, error) {
+			return &domain.User{
+				UserID:       userID,
+				Name:         "ユーザー１",
+				Status:       domain.UserStatusFrozen,
+				RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			}, nil
+		},
+		put: func(ctx context.Context, user *domain.User) error {
+			savedUser = user
+			return nil
+		},
+	}
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodPost, "/users/:userID/unfreeze", strings.NewReader(`{"operatorID": "OP1", "reason": "凍結解除理由"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+	c.SetParamNames("userID")
+	c.SetParamValues("U1")
+
+	if err := UnfreezeUser(c, userRepository, domain.SystemClock); err != nil {
+		t.Fatalf("ユースケースがエラーを返しました: %v", err)
+	}
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNoContent, recorder.Code)
+	}
+	if savedUser == nil {
+		t.Fatalf("ユーザーが保存されませんでした")
+	}
+	if savedUser.IsFrozen(domain.SystemClock) {
+		t.Errorf("凍結状態でないユーザーが保存されるはずですが、ステータスが %q のユーザーが保存されました", savedUser.Status)
+	}
+	if change := savedUser.LastStatusChange; change == nil || change.OperatorID != "OP1" || change.Reason != "凍結解除理由" {
+		t.Errorf("操作者 ID %q, 理由 %q のステータス変更の記録が残るはずですが、%+v が保存されました", "OP1", "凍結解除理由", change)
+	}
+}
+
+// 凍結状態でないユーザーの凍結を解除しようとした場合、ユーザーを保存せず、ETag も変わらないことのテスト。
+func TestUnfreezeUserNotFrozen(t *testing.T) {
+	userRepository := &MockUserRepository{
+		get: func(ctx context.Context, userID string) (*domain.User, error) {
+			return &domain.User{
+				UserID:       userID,
+				Name:         "ユーザー１",
+				Status:       domain.UserStatusNormal,
+				RegisteredAt: time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				Version:      1,
+			}, nil
+		},
+		put: func(ctx context.Context, user *domain.User) error {
+			t.Errorf("何も変更していないユーザーが保存されました: %+v", *user)
+			return nil
+		},
+	}
+
+	e := echo.New()
+	request := httptest.NewRequest(http.MethodPost, "/users/:userID/unfreeze", strings.NewReader(`{"operatorID": "OP1", "reason": "凍結解除理由"}`))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+	c := e.NewContext(request, recorder)
+	c.SetParamNames("userID")
+	c.SetParamValues("U1")
+
+	if err := UnfreezeUser(c, userRepository, domain.SystemClock); err != nil {
+		t.Fatalf("ユースケースがエラーを返しました: %v", err)
+	}
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", http.StatusNoContent, recorder.Code)
+	}
+	if etag := recorder.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("ETag ヘッダーは変更前のバージョンの %q のはずですが、%q が返りました", `"1"`, etag)
+	}
+}
+
+// UnfreezeUser ユースケースのエラー系のテスト。
+func TestUnfreezeUserError(t *testing.T) {
+	testCases := []struct {
+		name           string                                                         // テストケース名
+		body           string                                                         // リクエストボディ
+		get            func(ctx context.Context, userID string) (*domain.User, error) // リポジトリの Get
+		wantStatusCode int                                                            // 期待される HTTP ステータスコード
+		wantErrorCode  string                                                         // 期待されるエラーコード
+	}{
+		{
+			name:           "操作者 ID が省略された場合",
+			body:           `{"reason": "凍結解除理由"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "BadRequest",
+		},
+		{
+			name:           "理由が省略された場合",
+			body:           `{"operatorID": "OP1"}`,
+			wantStatusCode: http.StatusBadRequest,
+			wantErrorCode:  "BadRequest",
+		},
+		{
+			name: "ユーザーが見つからない場合",
+			body: `{"operatorID": "OP1", "reason": "凍結解除理由"}`,
+			get: func(ctx context.Context, userID string) (*domain.User, error) {
+				return nil, domain.ErrUserNotFound
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantErrorCode:  "UserNotFound",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userRepository := &MockUserRepository{get: tc.get}
+
+			e := echo.New()
+			request := httptest.NewRequest(http.MethodPost, "/users/:userID/unfreeze", strings.NewReader(tc.body))
+			request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c := e.NewContext(request, nil)
+			c.SetParamNames("userID")
+			c.SetParamValues("U1")
+
+			err := UnfreezeUser(c, userRepository, domain.SystemClock)
+			if err == nil {
+				t.Fatalf("ユースケースがエラーを返すはずですが、返しませんでした")
+			}
+
+			statusCode, errorResponse := ParseErrorResponse(t, err)
+			if statusCode != tc.wantStatusCode {
+				t.Errorf("期待される HTTP ステータスコードは %d ですが、%d が返りました", tc.wantStatusCode, statusCode)
+			}
+			if errorResponse.Code != tc.wantErrorCode {
+				t.Errorf("期待されるエラーコードは %s ですが、%s が返りました", tc.wantErrorCode, errorResponse.Code)
+			}
+		})
+	}
+}
@@ -1,2 +1,14 @@
 // usecase パッケージはユースケースを提供します。
 package usecase
+
+import "nekonoshiri/go-echo-sample/domain"
+
+// in に渡す、ユーザーのステータスの値の一覧を返します。
+// ステータスの一覧は domain パッケージの遷移表から作られるため、ここで重複して定義しないでください。
+func userStatusValues() []interface{} {
+	values := []interface{}{}
+	for _, status := range domain.UserStatuses() {
+		values = append(values, string(status))
+	}
+	return values
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"nekonoshiri/go-echo-sample/domain"
 
@@ -48,10 +49,13 @@ func ParseErrorResponse(t *testing.T, err error) (int, *ErrorResponse) {
 
 // テスト用の UserRepository。
 type MockUserRepository struct {
-	get    func(ctx context.Context, userID string) (*domain.User, error)
-	list   func(ctx context.Context, exclusiveStartKey string, limit int) (users []domain.User, lastEvaluatedKey string, err error)
-	put    func(ctx context.Context, user *domain.User) error
-	delete func(ctx context.Context, userID string) error
+	get                 func(ctx context.Context, userID string) (*domain.User, error)
+	getIncludingDeleted func(ctx context.Context, userID string) (*domain.User, error)
+	list                func(ctx context.Context, exclusiveStartKey string, limit int) (users []domain.User, lastEvaluatedKey string, err error)
+	listExpiredFrozen   func(ctx context.Context, now time.Time, exclusiveStartKey string, limit int) (users []domain.User, lastEvaluatedKey string, err error)
+	put                 func(ctx context.Context, user *domain.User) error
+	delete              func(ctx context.Context, userID string, deletedAt time.Time) (bool, error)
+	purge               func(ctx context.Context, deletedBefore time.Time) (int, error)
 }
 
 func (repo *MockUserRepository) Get(ctx context.Context, userID string) (*domain.User, error) {
@@ -61,6 +65,13 @@ func (repo *MockUserRepository) Get(ctx context.Context, userID string) (*domain
 	return nil, errors.New("実装されていません")
 }
 
+func (repo *MockUserRepository) GetIncludingDeleted(ctx context.Context, userID string) (*domain.User, error) {
+	if repo.getIncludingDeleted != nil {
+		return repo.getIncludingDeleted(ctx, userID)
+	}
+	return nil, errors.New("実装されていません")
+}
+
 func (repo *MockUserRepository) List(ctx context.Context, exclusiveStartKey string, limit int) (users []domain.User, lastEvaluatedKey string, err error) {
 	if repo.list != nil {
 		return repo.list(ctx, exclusiveStartKey, limit)
@@ -68,6 +79,13 @@ func (repo *MockUserRepository) List(ctx context.Context, exclusiveStartKey stri
 	return nil, "", errors.New("実装されていません")
 }
 
+func (repo *MockUserRepository) ListExpiredFrozen(ctx context.Context, now time.Time, exclusiveStartKey string, limit int) (users []domain.User, lastEvaluatedKey string, err error) {
+	if repo.listExpiredFrozen != nil {
+		return repo.listExpiredFrozen(ctx, now, exclusiveStartKey, limit)
+	}
+	return nil, "", errors.New("実装されていません")
+}
+
 func (repo *MockUserRepository) Put(ctx context.Context, user *domain.User) error {
 	if repo.put != nil {
 		return repo.put(ctx, user)
@@ -75,9 +93,16 @@ func (repo *MockUserRepository) Put(ctx context.Context, user *domain.User) erro
 	return errors.New("実装されていません")
 }
 
-func (repo *MockUserRepository) Delete(ctx context.Context, userID string) error {
+func (repo *MockUserRepository) Delete(ctx context.Context, userID string, deletedAt time.Time) (bool, error) {
 	if repo.delete != nil {
-		return repo.delete(ctx, userID)
+		return repo.delete(ctx, userID, deletedAt)
 	}
-	return errors.New("実装されていません")
+	return false, errors.New("実装されていません")
+}
+
+func (repo *MockUserRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	if repo.purge != nil {
+		return repo.purge(ctx, deletedBefore)
+	}
+	return 0, errors.New("実装されていません")
 }